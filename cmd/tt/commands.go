@@ -0,0 +1,344 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/yosakoo/task-traker/pkg/client"
+)
+
+func login(ctx context.Context, args []string) error {
+	fs, out := newFlagSet("login", "")
+	server := fs.String("server", "", "API URL, e.g. "+defaultServer)
+	email := fs.String("email", "", "email to sign in with, asked for when empty")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if *server != "" {
+		cfg.Server = *server
+	}
+
+	stdin := bufio.NewReader(os.Stdin)
+	if *email == "" {
+		if *email, err = prompt(stdin, "Email: "); err != nil {
+			return err
+		}
+	}
+	password, err := readPassword(stdin)
+	if err != nil {
+		return err
+	}
+
+	c := cfg.newClient()
+	if _, err := c.SignIn(ctx, client.SignInInput{Email: *email, Password: password}); err != nil {
+		return err
+	}
+	user, err := c.CurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+	if *out == "json" {
+		return out.print(user, nil)
+	}
+	fmt.Printf("Logged in as %s <%s>\n", user.Name, user.Email)
+	if user.VerifiedAt == nil {
+		fmt.Println("The email isn't verified yet, follow the link sent to it.")
+	}
+	return nil
+}
+
+func prompt(stdin *bufio.Reader, label string) (string, error) {
+	fmt.Fprint(os.Stderr, label)
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// readPassword reads the password without echoing it when stdin is a
+// terminal, otherwise it reads the first line, e.g. of a pipe.
+func readPassword(stdin *bufio.Reader) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return prompt(stdin, "")
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(password), nil
+}
+
+func add(ctx context.Context, args []string) error {
+	fs, out := newFlagSet("add", "<title>")
+	projectID := fs.Int("project", 0, "ID of the project the task belongs to")
+	parentID := fs.Int("parent", 0, "ID of the task this is a subtask of")
+	text := fs.String("text", "", "text of the task")
+	priority := fs.String("priority", "", "low, normal, high or urgent")
+	due := fs.String("due", "", "due date, YYYY-MM-DD or RFC 3339")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	title := strings.Join(fs.Args(), " ")
+	if title == "" {
+		fs.Usage()
+		return errUsage
+	}
+
+	input := client.TaskInput{
+		Title:    title,
+		Text:     *text,
+		Priority: *priority,
+	}
+	if *projectID != 0 {
+		input.ProjectID = projectID
+	}
+	if *parentID != 0 {
+		input.ParentID = parentID
+	}
+	if *due != "" {
+		dueAt, err := parseDate(*due)
+		if err != nil {
+			return err
+		}
+		input.DueAt = &dueAt
+	}
+
+	c, err := signedInClient()
+	if err != nil {
+		return err
+	}
+	taskID, err := c.CreateTask(ctx, input)
+	if err != nil {
+		return err
+	}
+	task, err := c.GetTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	return out.printTask(task)
+}
+
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", value)
+	}
+	return t, nil
+}
+
+// stringList is a flag that can be given more than once.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func list(ctx context.Context, args []string) error {
+	fs, out := newFlagSet("ls", "")
+	var opts client.TaskListOptions
+	var tags stringList
+	fs.StringVar(&opts.Status, "status", "", "only tasks in this status, e.g. pending")
+	fs.StringVar(&opts.Query, "q", "", "only tasks whose title or text contains this")
+	fs.Var(&tags, "tag", "only tasks with this tag, can be repeated")
+	fs.StringVar(&opts.TagMode, "tag-mode", "", "whether tasks need any or all of the tags")
+	fs.BoolVar(&opts.Overdue, "overdue", false, "only overdue tasks")
+	fs.StringVar(&opts.Sort, "sort", "", "id, created_at, updated_at, due_at, priority or title")
+	fs.StringVar(&opts.Order, "order", "", "asc or desc")
+	fs.IntVar(&opts.Limit, "limit", 0, "number of tasks to list")
+	fs.StringVar(&opts.Cursor, "cursor", "", "cursor of the page to list")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	opts.Tags = tags
+
+	c, err := signedInClient()
+	if err != nil {
+		return err
+	}
+	taskList, err := c.ListTasks(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	var tasks []client.Task
+	for _, group := range taskList.Groups {
+		tasks = append(tasks, group.Tasks...)
+	}
+	if err := out.printTasks(taskList, tasks); err != nil {
+		return err
+	}
+	if *out == "table" && taskList.NextCursor != "" {
+		printWarning("more tasks: tt ls -cursor %s", taskList.NextCursor)
+	}
+	return nil
+}
+
+func done(ctx context.Context, args []string) error {
+	fs, out := newFlagSet("done", "<id>")
+	force := fs.Bool("force", false, "complete the task even if it is blocked")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	taskID, err := parseTaskID(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	c, err := signedInClient()
+	if err != nil {
+		return err
+	}
+	status, err := doneStatus(ctx, c)
+	if err != nil {
+		return err
+	}
+	task, err := c.PatchTask(ctx, taskID, client.TaskPatch{Status: &status}, client.WriteOptions{Force: *force})
+	if err != nil {
+		return err
+	}
+	return out.printTask(task)
+}
+
+// doneStatus returns the first status of the user's workflow that completes tasks.
+func doneStatus(ctx context.Context, c *client.Client) (string, error) {
+	wf, err := c.GetWorkflow(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, status := range wf.Statuses {
+		if status.Category == client.CategoryDone {
+			return status.Name, nil
+		}
+	}
+	return "", errors.New("the workflow has no status that completes tasks")
+}
+
+func edit(ctx context.Context, args []string) error {
+	fs, out := newFlagSet("edit", "<id>")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	taskID, err := parseTaskID(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	c, err := signedInClient()
+	if err != nil {
+		return err
+	}
+	task, err := c.GetTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	text, err := editText(ctx, fmt.Sprintf("tt-task-%d-*.md", taskID), task.Text)
+	if err != nil {
+		return err
+	}
+	if text == task.Text {
+		printWarning("text unchanged, task not updated")
+		return nil
+	}
+
+	// the version makes the update fail if the task was changed meanwhile
+	task, err = c.PatchTask(ctx, taskID, client.TaskPatch{Text: &text}, client.WriteOptions{Version: task.Version})
+	if err != nil {
+		return err
+	}
+	return out.printTask(task)
+}
+
+// editText opens text in $VISUAL or $EDITOR, vi if neither is set, and
+// returns it as saved.
+func editText(ctx context.Context, pattern, text string) (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	// EDITOR may carry arguments, e.g. "code --wait"
+	fields := strings.Fields(editor)
+	cmd := exec.CommandContext(ctx, fields[0], append(fields[1:], f.Name())...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor: %w", err)
+	}
+
+	edited, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	// editors add a final newline the text didn't have
+	if !strings.HasSuffix(text, "\n") {
+		edited = bytes.TrimSuffix(edited, []byte("\n"))
+	}
+	return string(edited), nil
+}
+
+func remove(ctx context.Context, args []string) error {
+	fs, out := newFlagSet("rm", "<id>")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	taskID, err := parseTaskID(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	c, err := signedInClient()
+	if err != nil {
+		return err
+	}
+	if err := c.DeleteTask(ctx, taskID, client.WriteOptions{}); err != nil {
+		return err
+	}
+	if *out == "json" {
+		return out.print(map[string]int{"id": taskID}, nil)
+	}
+	fmt.Printf("Moved task %d to the trash\n", taskID)
+	return nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/yosakoo/task-traker/pkg/client"
+)
+
+const defaultServer = "http://localhost:8080/api"
+
+// config is what tt keeps between runs, in tt/config.json of the user's
+// config directory.
+type config struct {
+	Server string        `json:"server"`
+	Tokens client.Tokens `json:"tokens"`
+}
+
+func configPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tt", "config.json"), nil
+}
+
+// loadConfig reads the stored config, TT_SERVER overrides the stored server.
+func loadConfig() (*config, error) {
+	cfg := &config{Server: defaultServer}
+
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if server := os.Getenv("TT_SERVER"); server != "" {
+		cfg.Server = server
+	}
+	return cfg, nil
+}
+
+func (cfg *config) save() error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	// the file holds the refresh token, only the user may read it
+	return os.WriteFile(path, data, 0o600)
+}
+
+// newClient returns a client that stores tokens whenever they are refreshed.
+func (cfg *config) newClient() *client.Client {
+	return client.New(cfg.Server,
+		client.WithTokens(cfg.Tokens),
+		client.WithTokenHandler(func(tokens client.Tokens) {
+			cfg.Tokens = tokens
+			if err := cfg.save(); err != nil {
+				printWarning("could not store tokens: %v", err)
+			}
+		}),
+	)
+}
+
+// signedInClient is newClient for commands that need a signed in user.
+func signedInClient() (*client.Client, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Tokens.RefreshToken == "" {
+		return nil, errors.New(`not logged in, run "tt login" first`)
+	}
+	return cfg.newClient(), nil
+}
@@ -0,0 +1,61 @@
+// Command tt is a command-line client of the task tracker.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+const usage = `Usage: tt <command> [flags] [args]
+
+Commands:
+  login            sign in and store the tokens
+  add <title>      create a task
+  ls               list tasks
+  done <id>        complete a task
+  edit <id>        edit the text of a task in $EDITOR
+  rm <id>          move a task to the trash
+
+Every command takes -o table|json to choose the output format.
+Run "tt <command> -h" for the flags of a command.
+`
+
+// errUsage is returned by commands whose usage was printed, e.g. for bad flags.
+var errUsage = errors.New("usage")
+
+type command func(ctx context.Context, args []string) error
+
+var commands = map[string]command{
+	"login": login,
+	"add":   add,
+	"ls":    list,
+	"done":  done,
+	"edit":  edit,
+	"rm":    remove,
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "tt: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := cmd(ctx, os.Args[2:]); err != nil {
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		fmt.Fprintf(os.Stderr, "tt: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/yosakoo/task-traker/pkg/client"
+)
+
+// output is the format results are printed in, table or json.
+type output string
+
+func (o *output) String() string {
+	return string(*o)
+}
+
+func (o *output) Set(value string) error {
+	if value != "table" && value != "json" {
+		return fmt.Errorf("must be table or json")
+	}
+	*o = output(value)
+	return nil
+}
+
+// newFlagSet returns the flags of a command, with -o for the output format.
+func newFlagSet(name, args string) (*flag.FlagSet, *output) {
+	out := output("table")
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Var(&out, "o", "output format, table or json")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s\n\nFlags:\n", strings.TrimSpace("tt "+name+" [flags] "+args))
+		fs.PrintDefaults()
+	}
+	return fs, &out
+}
+
+func (o output) print(v any, table func(w *tabwriter.Writer)) error {
+	if o == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	table(w)
+	return w.Flush()
+}
+
+func (o output) printTasks(v any, tasks []client.Task) error {
+	return o.print(v, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "ID\tSTATUS\tPRIORITY\tDUE\tTAGS\tTITLE")
+		for _, task := range tasks {
+			tags := make([]string, 0, len(task.Tags))
+			for _, tag := range task.Tags {
+				tags = append(tags, tag.Name)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", task.ID, task.Status, task.Priority,
+				dueDate(task), strings.Join(tags, ","), task.Title)
+		}
+	})
+}
+
+func (o output) printTask(task *client.Task) error {
+	return o.printTasks(task, []client.Task{*task})
+}
+
+func dueDate(task client.Task) string {
+	if task.DueAt == nil {
+		return "-"
+	}
+	due := task.DueAt.Local().Format(time.DateOnly)
+	if task.IsOverdue {
+		due += " (overdue)"
+	}
+	return due
+}
+
+func printWarning(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "tt: "+format+"\n", args...)
+}
+
+func parseTaskID(args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("expected one task ID")
+	}
+	taskID, err := strconv.Atoi(args[0])
+	if err != nil || taskID <= 0 {
+		return 0, fmt.Errorf("invalid task ID %q", args[0])
+	}
+	return taskID, nil
+}
@@ -9,10 +9,13 @@ import (
     "time"
 
     "github.com/yosakoo/task-traker/internal/config"
+    grpcdelivery "github.com/yosakoo/task-traker/internal/delivery/grpc"
     "github.com/yosakoo/task-traker/internal/delivery/http"
+    "github.com/yosakoo/task-traker/internal/delivery/http/v1"
     "github.com/yosakoo/task-traker/internal/repository"
     "github.com/yosakoo/task-traker/internal/service"
     "github.com/yosakoo/task-traker/pkg/auth"
+    "github.com/yosakoo/task-traker/pkg/blob"
     "github.com/yosakoo/task-traker/pkg/hash"
     "github.com/yosakoo/task-traker/pkg/logger"
     "github.com/yosakoo/task-traker/pkg/postgres"
@@ -54,6 +57,11 @@ func Run(cfg *config.Config) {
 
     l.Info("RabbitMQ connected")
 
+    blobs, err := blob.NewLocalStore(cfg.Attachments.Dir)
+    if err != nil {
+        l.Fatal(fmt.Errorf("app - Run - blob.NewLocalStore: %w", err))
+    }
+
     repos := repo.NewRepositories(pg)
     services := service.NewServices(service.Deps{
         Repos:           repos,
@@ -63,9 +71,23 @@ func Run(cfg *config.Config) {
         QueueConn: rmqConn,
         AccessTokenTTL:  time.Minute * 1,
         RefreshTokenTTL: time.Hour * 24 * 7,
+        Blobs:           blobs,
+        MaxAttachmentSize: cfg.Attachments.MaxSize,
+        AttachmentTypes:   cfg.Attachments.AllowedTypes,
+        IdempotencyTTL:    cfg.Idempotency.TTL,
+        Accounts: service.AccountOptions{
+            PasswordResetTTL:           cfg.Accounts.PasswordResetTTL,
+            PasswordResetURL:           cfg.Accounts.PasswordResetURL,
+            VerificationTTL:            cfg.Accounts.VerificationTTL,
+            VerificationURL:            cfg.Accounts.VerificationURL,
+            VerificationResendInterval: cfg.Accounts.VerificationResendInterval,
+            UnverifiedGracePeriod:      cfg.Accounts.UnverifiedGracePeriod,
+        },
     })
 
-    handlers := http.NewHandler(services, tokenManager)
+    handlers := http.NewHandler(services, tokenManager, v1.Options{
+        RequireIfMatch: cfg.Server.RequireIfMatch,
+    })
     srv := server.NewServer(cfg, handlers.Init(l))
     
 
@@ -75,10 +97,24 @@ func Run(cfg *config.Config) {
         }
     }()
 
+    grpcSrv := grpcdelivery.NewServer(cfg.GRPC.Port, services, tokenManager, l)
+    go func() {
+        if err := grpcSrv.Run(); err != nil {
+            l.Error(fmt.Errorf("grpc server: %w", err))
+        }
+    }()
+
+    jobsCtx, stopJobs := context.WithCancel(context.Background())
+    defer stopJobs()
+    go purgeTrash(jobsCtx, services.Trash, cfg.Trash, l)
+    go purgeIdempotencyKeys(jobsCtx, services.Idempotency, cfg.Idempotency.PurgeInterval, l)
+
     quit := make(chan os.Signal, 1)
     signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
     <-quit
 
+    stopJobs()
+
     const timeout = 5 * time.Second
     ctx, cancel := context.WithTimeout(context.Background(), timeout)
     defer cancel()
@@ -86,4 +122,45 @@ func Run(cfg *config.Config) {
     if err := srv.Stop(ctx); err != nil {
         l.Error(fmt.Errorf("failed to stop server: %v", err))
     }
+
+    // the HTTP server may have used up its deadline, gRPC gets its own
+    grpcCtx, cancelGRPC := context.WithTimeout(context.Background(), timeout)
+    defer cancelGRPC()
+    grpcSrv.Stop(grpcCtx)
+}
+
+// purgeTrash removes expired tasks from the trash until ctx is done.
+func purgeTrash(ctx context.Context, trash service.Trash, cfg config.Trash, l *logger.Logger) {
+    ticker := time.NewTicker(cfg.PurgeInterval)
+    defer ticker.Stop()
+
+    for {
+        if err := trash.Purge(ctx, cfg.Retention); err != nil && ctx.Err() == nil {
+            l.Error(fmt.Errorf("app - purgeTrash - trash.Purge: %w", err))
+        }
+
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+    }
+}
+
+// purgeIdempotencyKeys removes expired idempotency keys until ctx is done.
+func purgeIdempotencyKeys(ctx context.Context, keys service.Idempotency, interval time.Duration, l *logger.Logger) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+
+    for {
+        if err := keys.Purge(ctx); err != nil && ctx.Err() == nil {
+            l.Error(fmt.Errorf("app - purgeIdempotencyKeys - keys.Purge: %w", err))
+        }
+
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+    }
 }
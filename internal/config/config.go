@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -10,14 +11,24 @@ import (
 type (
 	Config struct {
 		Server   `yaml:"server"`
+		GRPC     `yaml:"grpc"`
 		RabbitMQ `yaml:"rabbitmq"`
 		PG
-		Log `yaml:"logger"`
+		Log         `yaml:"logger"`
+		Attachments `yaml:"attachments"`
+		Trash       `yaml:"trash"`
+		Idempotency `yaml:"idempotency"`
+		Accounts    `yaml:"accounts"`
 	}
 	Server struct {
 		Port         string `yaml:"port"`
 		ReadTimeout  int    `yaml:"read_timeout"`
 		WriteTimeout int    `yaml:"write_timeout"`
+		// RequireIfMatch makes task changes without an If-Match header fail.
+		RequireIfMatch bool `yaml:"require_if_match" env:"REQUIRE_IF_MATCH" env-default:"false"`
+	}
+	GRPC struct {
+		Port string `yaml:"port" env:"GRPC_PORT" env-default:":9090"`
 	}
 	RabbitMQ struct {
 		URL          string
@@ -32,6 +43,32 @@ type (
 	Log struct {
 		Level string `yaml:"log_level"`
 	}
+	// Trash tasks are purged Retention after deletion, checked every PurgeInterval.
+	Trash struct {
+		Retention     time.Duration `yaml:"retention" env-default:"720h"`
+		PurgeInterval time.Duration `yaml:"purge_interval" env-default:"1h"`
+	}
+	// Idempotency keys are kept for TTL, expired ones are removed every PurgeInterval.
+	Idempotency struct {
+		TTL           time.Duration `yaml:"ttl" env:"IDEMPOTENCY_TTL" env-default:"24h"`
+		PurgeInterval time.Duration `yaml:"purge_interval" env-default:"1h"`
+	}
+	// Accounts tune how users verify and recover their accounts, reset links point at
+	// PasswordResetURL and verification links at VerificationURL. Unverified users can't
+	// create tasks once UnverifiedGracePeriod has passed since sign-up, 0 turns it off.
+	Accounts struct {
+		PasswordResetTTL           time.Duration `yaml:"password_reset_ttl" env-default:"1h"`
+		PasswordResetURL           string        `yaml:"password_reset_url" env:"PASSWORD_RESET_URL" env-default:"http://localhost:8080/reset-password"`
+		VerificationTTL            time.Duration `yaml:"verification_ttl" env-default:"48h"`
+		VerificationURL            string        `yaml:"verification_url" env:"VERIFICATION_URL" env-default:"http://localhost:8080/verify-email"`
+		VerificationResendInterval time.Duration `yaml:"verification_resend_interval" env-default:"1m"`
+		UnverifiedGracePeriod      time.Duration `yaml:"unverified_grace_period" env-default:"168h"`
+	}
+	Attachments struct {
+		Dir          string   `yaml:"dir" env:"ATTACHMENTS_DIR" env-default:"./data/attachments"`
+		MaxSize      int64    `yaml:"max_size" env-default:"10485760"`
+		AllowedTypes []string `yaml:"allowed_types" env-default:"image/png,image/jpeg,image/gif,image/webp,application/pdf,text/plain,application/zip"`
+	}
 )
 
 func NewConfig() (*Config, error) {
@@ -42,8 +79,15 @@ func NewConfig() (*Config, error) {
 		return nil, fmt.Errorf("config error: %w", err)
 	}
 
+	// the intervals drive tickers, which don't take zero or less
+	if cfg.Trash.PurgeInterval <= 0 {
+		return nil, fmt.Errorf("config error: trash.purge_interval must be positive, got %s", cfg.Trash.PurgeInterval)
+	}
+	if cfg.Idempotency.PurgeInterval <= 0 {
+		return nil, fmt.Errorf("config error: idempotency.purge_interval must be positive, got %s", cfg.Idempotency.PurgeInterval)
+	}
+
 	cfg.PG.URL = os.Getenv("PG_URL")
 	cfg.RabbitMQ.URL = os.Getenv("RABBITMQ_URL")
-	fmt.Println(cfg.PG.URL)
 	return cfg, nil
 }
@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/yosakoo/task-traker/pkg/api/tasktracker/v1"
+	"github.com/yosakoo/task-traker/pkg/auth"
+)
+
+// publicMethods can be called without an access token.
+var publicMethods = map[string]bool{
+	tasktrackerv1.UserService_SignUp_FullMethodName:        true,
+	tasktrackerv1.UserService_SignIn_FullMethodName:        true,
+	tasktrackerv1.UserService_RefreshTokens_FullMethodName: true,
+}
+
+type userIDKey struct{}
+
+// userID returns the ID of the user the call was authenticated as.
+func userID(ctx context.Context) int {
+	return ctx.Value(userIDKey{}).(int)
+}
+
+// authenticator checks the access token sent as "authorization: Bearer
+// <token>" metadata, the same token the HTTP API takes.
+type authenticator struct {
+	tokenManager auth.TokenManager
+}
+
+func (a *authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if publicMethods[method] {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "not authenticated")
+	}
+	accessToken := strings.TrimPrefix(values[0], "Bearer ")
+
+	userIdStr, err := a.tokenManager.Parse(accessToken)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "not authenticated")
+	}
+	userId, err := strconv.Atoi(userIdStr)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return context.WithValue(ctx, userIDKey{}, userId), nil
+}
+
+func (a *authenticator) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := a.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *authenticator) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
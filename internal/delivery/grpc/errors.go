@@ -0,0 +1,57 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/yosakoo/task-traker/internal/domain"
+)
+
+// domainCodes maps errors returned by services to status codes, the error
+// text becomes the message.
+var domainCodes = []struct {
+	err  error
+	code codes.Code
+}{
+	{domain.ErrUserNotFound, codes.NotFound},
+	{domain.ErrUserAlreadyExists, codes.AlreadyExists},
+	{domain.ErrTokenExpired, codes.Unauthenticated},
+	{domain.ErrEmailNotVerified, codes.PermissionDenied},
+	{domain.ErrTaskNotFound, codes.NotFound},
+	{domain.ErrTaskForbidden, codes.PermissionDenied},
+	{domain.ErrTaskVersionMismatch, codes.Aborted},
+	{domain.ErrInvalidTaskFilter, codes.InvalidArgument},
+	{domain.ErrInvalidCursor, codes.InvalidArgument},
+	{domain.ErrInvalidStatus, codes.InvalidArgument},
+	{domain.ErrInvalidPriority, codes.InvalidArgument},
+	{domain.ErrInvalidRecurrence, codes.InvalidArgument},
+	{domain.ErrStatusTransition, codes.FailedPrecondition},
+	{domain.ErrSubtaskCycle, codes.FailedPrecondition},
+	{domain.ErrSubtaskTooDeep, codes.FailedPrecondition},
+	{domain.ErrDependencyCycle, codes.FailedPrecondition},
+	{domain.ErrTaskBlocked, codes.FailedPrecondition},
+	{domain.ErrOccurrenceExists, codes.AlreadyExists},
+	{domain.ErrProjectNotFound, codes.NotFound},
+	{domain.ErrProjectForbidden, codes.PermissionDenied},
+	{domain.ErrWorkflowNotFound, codes.NotFound},
+}
+
+// toStatus converts err to a status error. Errors unknown to the mapping are
+// internal, their text isn't shown to the client.
+func toStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	for _, c := range domainCodes {
+		if errors.Is(err, c.err) {
+			return status.Error(c.code, err.Error())
+		}
+	}
+	return status.Error(codes.Internal, "internal error")
+}
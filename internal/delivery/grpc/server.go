@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/yosakoo/task-traker/internal/service"
+	"github.com/yosakoo/task-traker/pkg/api/tasktracker/v1"
+	"github.com/yosakoo/task-traker/pkg/auth"
+	"github.com/yosakoo/task-traker/pkg/logger"
+)
+
+// Server serves the gRPC API on its own port, next to the HTTP API.
+type Server struct {
+	server *grpc.Server
+	port   string
+	// shutdown is closed when Stop is called, it ends watch streams.
+	shutdown chan struct{}
+}
+
+func NewServer(port string, services *service.Services, tokenManager auth.TokenManager, log logger.Interface) *Server {
+	authn := &authenticator{tokenManager: tokenManager}
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryLogger(log), authn.unary),
+		grpc.ChainStreamInterceptor(streamLogger(log), authn.stream),
+	)
+	tasktrackerv1.RegisterUserServiceServer(server, &userServer{services: services})
+	shutdown := make(chan struct{})
+	tasktrackerv1.RegisterTaskServiceServer(server, &taskServer{services: services, shutdown: shutdown})
+
+	return &Server{
+		server:   server,
+		port:     port,
+		shutdown: shutdown,
+	}
+}
+
+func (s *Server) Run() error {
+	lis, err := net.Listen("tcp", s.port)
+	if err != nil {
+		return err
+	}
+	return s.server.Serve(lis)
+}
+
+// Stop ends watch streams, lets running calls finish until ctx is done and
+// cancels the rest.
+func (s *Server) Stop(ctx context.Context) {
+	close(s.shutdown)
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.server.Stop()
+	}
+}
+
+func unaryLogger(log logger.Interface) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		t1 := time.Now()
+		resp, err := handler(ctx, req)
+		log.Info(fmt.Sprintf("grpc method: %s, code: %s, duration: %s", info.FullMethod, status.Code(err), time.Since(t1).String()))
+		return resp, err
+	}
+}
+
+func streamLogger(log logger.Interface) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		t1 := time.Now()
+		err := handler(srv, ss)
+		log.Info(fmt.Sprintf("grpc stream: %s, code: %s, duration: %s", info.FullMethod, status.Code(err), time.Since(t1).String()))
+		return err
+	}
+}
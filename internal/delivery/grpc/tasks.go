@@ -0,0 +1,256 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/yosakoo/task-traker/internal/domain"
+	"github.com/yosakoo/task-traker/internal/service"
+	"github.com/yosakoo/task-traker/pkg/api/tasktracker/v1"
+)
+
+type taskServer struct {
+	tasktrackerv1.UnimplementedTaskServiceServer
+	services *service.Services
+	shutdown <-chan struct{}
+}
+
+func (s *taskServer) CreateTask(ctx context.Context, req *tasktrackerv1.CreateTaskRequest) (*tasktrackerv1.CreateTaskResponse, error) {
+	input, err := taskInputFromProto(req.GetTask())
+	if err != nil {
+		return nil, err
+	}
+
+	taskID, err := s.services.Tasks.CreateTask(ctx, userID(ctx), input)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	task, err := s.services.Tasks.GetTaskByID(ctx, userID(ctx), taskID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasktrackerv1.CreateTaskResponse{Task: taskToProto(task)}, nil
+}
+
+func (s *taskServer) GetTask(ctx context.Context, req *tasktrackerv1.GetTaskRequest) (*tasktrackerv1.GetTaskResponse, error) {
+	task, err := s.services.Tasks.GetTaskByID(ctx, userID(ctx), int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasktrackerv1.GetTaskResponse{Task: taskToProto(task)}, nil
+}
+
+func (s *taskServer) ListTasks(ctx context.Context, req *tasktrackerv1.ListTasksRequest) (*tasktrackerv1.ListTasksResponse, error) {
+	list, err := s.services.Tasks.GetUserTasks(ctx, userID(ctx), service.TaskListInput{
+		ProjectID: intPtr(req.ProjectId),
+		Status:    req.GetStatus(),
+		Search:    req.GetQuery(),
+		Tags:      req.GetTags(),
+		TagMatch:  req.GetTagMode(),
+		Overdue:   req.GetOverdue(),
+		SortBy:    req.GetSort(),
+		Order:     req.GetOrder(),
+		Limit:     int(req.GetLimit()),
+		Cursor:    req.GetCursor(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	groups := make([]*tasktrackerv1.TaskGroup, 0, len(list.Groups))
+	for _, group := range list.Groups {
+		tasks := make([]*tasktrackerv1.Task, 0, len(group.Tasks))
+		for _, task := range group.Tasks {
+			tasks = append(tasks, taskToProto(task))
+		}
+		groups = append(groups, &tasktrackerv1.TaskGroup{
+			Status:   group.Status,
+			Category: group.Category,
+			Tasks:    tasks,
+		})
+	}
+	return &tasktrackerv1.ListTasksResponse{Groups: groups, NextCursor: list.NextCursor}, nil
+}
+
+func (s *taskServer) UpdateTask(ctx context.Context, req *tasktrackerv1.UpdateTaskRequest) (*tasktrackerv1.UpdateTaskResponse, error) {
+	input, err := taskInputFromProto(req.GetTask())
+	if err != nil {
+		return nil, err
+	}
+	input.Version = int(req.GetVersion())
+	input.Force = req.GetForce()
+
+	err = s.services.Tasks.UpdateTask(ctx, userID(ctx), int(req.GetId()), input)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	task, err := s.services.Tasks.GetTaskByID(ctx, userID(ctx), int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasktrackerv1.UpdateTaskResponse{Task: taskToProto(task)}, nil
+}
+
+func (s *taskServer) DeleteTask(ctx context.Context, req *tasktrackerv1.DeleteTaskRequest) (*tasktrackerv1.DeleteTaskResponse, error) {
+	err := s.services.Tasks.DeleteTask(ctx, userID(ctx), int(req.GetId()), int(req.GetVersion()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasktrackerv1.DeleteTaskResponse{}, nil
+}
+
+func (s *taskServer) WatchTasks(req *tasktrackerv1.WatchTasksRequest, stream grpc.ServerStreamingServer[tasktrackerv1.WatchTasksResponse]) error {
+	// watches never end on their own, so they are ended when the server
+	// shuts down and clients resume elsewhere with the last event they got
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.shutdown:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err := s.services.Tasks.WatchTasks(ctx, userID(ctx), req.GetAfterEventId(), func(events []service.TaskEventOut) error {
+		// tasks are sent as they are now, so each is looked up once per batch
+		tasks := make(map[int]*tasktrackerv1.Task)
+		for _, event := range events {
+			task, seen := tasks[event.TaskID]
+			if !seen {
+				out, err := s.services.Tasks.GetTaskByID(ctx, userID(ctx), event.TaskID)
+				if err == nil {
+					task = taskToProto(out)
+				} else if !errors.Is(err, domain.ErrTaskNotFound) {
+					return err
+				}
+				tasks[event.TaskID] = task
+			}
+
+			err := stream.Send(&tasktrackerv1.WatchTasksResponse{
+				Event: taskEventToProto(event),
+				Task:  task,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	select {
+	case <-s.shutdown:
+		return status.Error(codes.Unavailable, "server is shutting down")
+	default:
+	}
+	return toStatus(err)
+}
+
+func taskInputFromProto(task *tasktrackerv1.TaskInput) (service.TaskInput, error) {
+	if task == nil || task.GetTitle() == "" {
+		return service.TaskInput{}, status.Error(codes.InvalidArgument, "task title is required")
+	}
+
+	input := service.TaskInput{
+		ProjectID:    intPtr(task.ProjectId),
+		ParentID:     intPtr(task.ParentId),
+		Title:        task.GetTitle(),
+		Status:       task.GetStatus(),
+		Text:         task.GetText(),
+		Priority:     task.GetPriority(),
+		AutoComplete: task.AutoComplete,
+		Recurrence:   task.Recurrence,
+	}
+	if task.DueAt != nil {
+		dueAt := task.DueAt.AsTime()
+		input.DueAt = &dueAt
+	}
+	return input, nil
+}
+
+func taskToProto(task service.TaskOut) *tasktrackerv1.Task {
+	tags := make([]*tasktrackerv1.Tag, 0, len(task.Tags))
+	for _, tag := range task.Tags {
+		tags = append(tags, &tasktrackerv1.Tag{
+			Id:    int64(tag.ID),
+			Name:  tag.Name,
+			Color: tag.Color,
+		})
+	}
+
+	return &tasktrackerv1.Task{
+		Id:           int64(task.ID),
+		ProjectId:    int64Ptr(task.ProjectID),
+		ParentId:     int64Ptr(task.ParentID),
+		Status:       task.Status,
+		Title:        task.Title,
+		Text:         task.Text,
+		Priority:     task.Priority,
+		DueAt:        timestamp(task.DueAt),
+		IsOverdue:    task.IsOverdue,
+		CompletedAt:  timestamp(task.CompletedAt),
+		CreatedAt:    timestamppb.New(task.CreatedAt),
+		UpdatedAt:    timestamppb.New(task.UpdatedAt),
+		AutoComplete: task.AutoComplete,
+		Progress: &tasktrackerv1.Progress{
+			Done:  int32(task.Progress.Done),
+			Total: int32(task.Progress.Total),
+			Text:  task.Progress.Text,
+		},
+		Tags:       tags,
+		BlockedBy:  int64s(task.BlockedBy),
+		Blocking:   int64s(task.Blocking),
+		Recurrence: task.Recurrence,
+		SeriesId:   int64Ptr(task.SeriesID),
+		Occurrence: int32(task.Occurrence),
+		Version:    int32(task.Version),
+	}
+}
+
+func taskEventToProto(event service.TaskEventOut) *tasktrackerv1.TaskEvent {
+	return &tasktrackerv1.TaskEvent{
+		Id:        event.ID,
+		TaskId:    int64(event.TaskID),
+		Action:    event.Action,
+		Field:     event.Field,
+		OldValue:  event.OldValue,
+		NewValue:  event.NewValue,
+		CreatedAt: timestamppb.New(event.CreatedAt),
+	}
+}
+
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func intPtr(n *int64) *int {
+	if n == nil {
+		return nil
+	}
+	v := int(*n)
+	return &v
+}
+
+func int64Ptr(n *int) *int64 {
+	if n == nil {
+		return nil
+	}
+	v := int64(*n)
+	return &v
+}
+
+func int64s(ns []int) []int64 {
+	out := make([]int64, 0, len(ns))
+	for _, n := range ns {
+		out = append(out, int64(n))
+	}
+	return out
+}
@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/yosakoo/task-traker/internal/domain"
+	"github.com/yosakoo/task-traker/internal/service"
+	"github.com/yosakoo/task-traker/pkg/api/tasktracker/v1"
+)
+
+type userServer struct {
+	tasktrackerv1.UnimplementedUserServiceServer
+	services *service.Services
+}
+
+func tokensToProto(tokens service.Tokens) *tasktrackerv1.Tokens {
+	return &tasktrackerv1.Tokens{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}
+}
+
+func (s *userServer) SignUp(ctx context.Context, req *tasktrackerv1.SignUpRequest) (*tasktrackerv1.SignUpResponse, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	if _, err := mail.ParseAddress(req.GetEmail()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "email must be a valid email address")
+	}
+	if len(req.GetPassword()) < 6 {
+		return nil, status.Error(codes.InvalidArgument, "password must be at least 6 characters long")
+	}
+
+	tokens, err := s.services.Users.SignUp(ctx, service.UserSignUpInput{
+		Name:     req.GetName(),
+		Email:    req.GetEmail(),
+		Password: req.GetPassword(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasktrackerv1.SignUpResponse{Tokens: tokensToProto(tokens)}, nil
+}
+
+func (s *userServer) SignIn(ctx context.Context, req *tasktrackerv1.SignInRequest) (*tasktrackerv1.SignInResponse, error) {
+	if req.GetEmail() == "" || req.GetPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email and password are required")
+	}
+
+	tokens, err := s.services.Users.SignIn(ctx, service.UserSignInInput{
+		Email:    req.GetEmail(),
+		Password: req.GetPassword(),
+	})
+	if err != nil {
+		// an unknown email and a wrong password look the same to the client
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, status.Error(codes.Unauthenticated, "invalid email or password")
+		}
+		return nil, toStatus(err)
+	}
+	return &tasktrackerv1.SignInResponse{Tokens: tokensToProto(tokens)}, nil
+}
+
+func (s *userServer) RefreshTokens(ctx context.Context, req *tasktrackerv1.RefreshTokensRequest) (*tasktrackerv1.RefreshTokensResponse, error) {
+	if req.GetRefreshToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh_token is required")
+	}
+
+	tokens, err := s.services.Users.RefreshTokens(ctx, req.GetRefreshToken())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasktrackerv1.RefreshTokensResponse{Tokens: tokensToProto(tokens)}, nil
+}
+
+func (s *userServer) GetCurrentUser(ctx context.Context, req *tasktrackerv1.GetCurrentUserRequest) (*tasktrackerv1.GetCurrentUserResponse, error) {
+	user, err := s.services.Users.GetUserByID(ctx, userID(ctx))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tasktrackerv1.GetCurrentUserResponse{
+		User: &tasktrackerv1.User{
+			Id:    int64(user.ID),
+			Name:  user.Name,
+			Email: user.Email,
+		},
+	}, nil
+}
@@ -0,0 +1,71 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yosakoo/task-traker/internal/domain"
+)
+
+// domainCodes maps errors returned by services to the codes reported in the
+// extensions of a GraphQL error, the error text becomes the message.
+var domainCodes = []struct {
+	err  error
+	code string
+}{
+	{domain.ErrUserNotFound, "NOT_FOUND"},
+	{domain.ErrEmailNotVerified, "FORBIDDEN"},
+	{domain.ErrTaskNotFound, "NOT_FOUND"},
+	{domain.ErrTaskForbidden, "FORBIDDEN"},
+	{domain.ErrTaskVersionMismatch, "VERSION_MISMATCH"},
+	{domain.ErrInvalidTaskFilter, "BAD_USER_INPUT"},
+	{domain.ErrInvalidCursor, "BAD_USER_INPUT"},
+	{domain.ErrInvalidStatus, "BAD_USER_INPUT"},
+	{domain.ErrInvalidPriority, "BAD_USER_INPUT"},
+	{domain.ErrInvalidRecurrence, "BAD_USER_INPUT"},
+	{domain.ErrStatusTransition, "FAILED_PRECONDITION"},
+	{domain.ErrSubtaskCycle, "FAILED_PRECONDITION"},
+	{domain.ErrSubtaskTooDeep, "FAILED_PRECONDITION"},
+	{domain.ErrDependencyCycle, "FAILED_PRECONDITION"},
+	{domain.ErrTaskBlocked, "FAILED_PRECONDITION"},
+	{domain.ErrOccurrenceExists, "CONFLICT"},
+	{domain.ErrProjectNotFound, "NOT_FOUND"},
+	{domain.ErrProjectForbidden, "FORBIDDEN"},
+	{domain.ErrWorkflowNotFound, "NOT_FOUND"},
+}
+
+// resolverError is an error with a code for clients to tell errors apart.
+type resolverError struct {
+	message string
+	code    string
+}
+
+func (e resolverError) Error() string {
+	return e.message
+}
+
+func (e resolverError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+func badInput(message string) error {
+	return resolverError{message: message, code: "BAD_USER_INPUT"}
+}
+
+// toResolverError converts err for a resolver to return. Errors unknown to
+// the mapping are internal, their text isn't shown to the client.
+func toResolverError(err error) error {
+	var re resolverError
+	if errors.As(err, &re) {
+		return re
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return resolverError{message: err.Error(), code: "CANCELED"}
+	}
+	for _, c := range domainCodes {
+		if errors.Is(err, c.err) {
+			return resolverError{message: err.Error(), code: c.code}
+		}
+	}
+	return resolverError{message: "internal error", code: "INTERNAL"}
+}
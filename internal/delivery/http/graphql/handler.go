@@ -0,0 +1,37 @@
+// Package graphql serves the GraphQL API, it lets clients fetch tasks along
+// with what they reference in one request.
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/yosakoo/task-traker/internal/service"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// maxDepth keeps clients from nesting relations without end.
+const maxDepth = 10
+
+// NewHandler returns the handler of GraphQL requests. It expects the user to
+// be authenticated the way v1.Handler.AuthMiddleware does.
+func NewHandler(services *service.Services) http.Handler {
+	handler := &relay.Handler{
+		Schema: graphql.MustParseSchema(schema, &Resolver{services: services}, graphql.MaxDepth(maxDepth)),
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), loadersKey{}, newLoaders(services))
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func userID(ctx context.Context) int {
+	return ctx.Value("user_id").(int)
+}
@@ -0,0 +1,237 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/yosakoo/task-traker/internal/service"
+)
+
+// loader batches lookups by key the way dataloaders do: keys that are
+// asked for or prefetched while no batch is running are fetched together,
+// and each key is fetched once per request.
+type loader[K comparable, V any] struct {
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	results map[K]*result[V]
+	pending []K
+}
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	found bool
+	err   error
+}
+
+func newLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:   fetch,
+		results: make(map[K]*result[V]),
+	}
+}
+
+// prime stores a value that is already known, so it isn't fetched again.
+func (l *loader[K, V]) prime(key K, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.results[key]; ok {
+		return
+	}
+	r := &result[V]{done: make(chan struct{}), value: value, found: true}
+	close(r.done)
+	l.results[key] = r
+}
+
+// prefetch queues keys to be fetched with the next batch.
+func (l *loader[K, V]) prefetch(keys ...K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		l.queue(key)
+	}
+}
+
+func (l *loader[K, V]) queue(key K) *result[V] {
+	if r, ok := l.results[key]; ok {
+		return r
+	}
+	r := &result[V]{done: make(chan struct{})}
+	l.results[key] = r
+	l.pending = append(l.pending, key)
+	return r
+}
+
+// load returns the value for key, found is false if there is none.
+func (l *loader[K, V]) load(ctx context.Context, key K) (value V, found bool, err error) {
+	l.mu.Lock()
+	r := l.queue(key)
+	keys := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+
+	if len(keys) > 0 {
+		l.dispatch(ctx, keys)
+	}
+
+	select {
+	case <-r.done:
+		return r.value, r.found, r.err
+	case <-ctx.Done():
+		return value, false, ctx.Err()
+	}
+}
+
+// forget drops the values loaded so far, so that they are fetched again.
+// Keys that are being fetched are kept, their loads are waited for.
+func (l *loader[K, V]) forget() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key, r := range l.results {
+		select {
+		case <-r.done:
+			delete(l.results, key)
+		default:
+		}
+	}
+}
+
+// loadMany returns the values found for keys in the order of keys.
+func (l *loader[K, V]) loadMany(ctx context.Context, keys []K) ([]V, error) {
+	l.prefetch(keys...)
+
+	values := make([]V, 0, len(keys))
+	for _, key := range keys {
+		value, found, err := l.load(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+func (l *loader[K, V]) dispatch(ctx context.Context, keys []K) {
+	values, err := l.fetch(ctx, keys)
+
+	l.mu.Lock()
+	results := make([]*result[V], 0, len(keys))
+	for _, key := range keys {
+		results = append(results, l.results[key])
+	}
+	l.mu.Unlock()
+
+	for i, r := range results {
+		r.value, r.found = values[keys[i]]
+		r.err = err
+		close(r.done)
+	}
+}
+
+// loaders are the loaders of one request, they load what the signed in
+// user can see.
+type loaders struct {
+	tasks    *loader[int, service.TaskOut]
+	subtasks *loader[int, []service.TaskOut]
+	projects *loader[int, service.ProjectOut]
+
+	projectTasks *loader[projectTasksKey, service.TaskList]
+}
+
+// projectTasksKey asks for the tasks of a project that match a filter, the
+// filter is the JSON of the list input so that equal filters compare equal.
+type projectTasksKey struct {
+	projectID int
+	filter    string
+}
+
+func newProjectTasksKey(projectID int, input service.TaskListInput) (projectTasksKey, error) {
+	filter, err := json.Marshal(input)
+	if err != nil {
+		return projectTasksKey{}, err
+	}
+	return projectTasksKey{projectID: projectID, filter: string(filter)}, nil
+}
+
+func newLoaders(services *service.Services) *loaders {
+	return &loaders{
+		tasks: newLoader(func(ctx context.Context, taskIDs []int) (map[int]service.TaskOut, error) {
+			tasks, err := services.Tasks.GetTasksByIDs(ctx, userID(ctx), taskIDs)
+			if err != nil {
+				return nil, err
+			}
+			byID := make(map[int]service.TaskOut, len(tasks))
+			for _, task := range tasks {
+				byID[task.ID] = task
+			}
+			return byID, nil
+		}),
+		subtasks: newLoader(func(ctx context.Context, parentIDs []int) (map[int][]service.TaskOut, error) {
+			tasks, err := services.Tasks.GetSubtasksOf(ctx, userID(ctx), parentIDs)
+			if err != nil {
+				return nil, err
+			}
+			byParent := make(map[int][]service.TaskOut, len(parentIDs))
+			for _, task := range tasks {
+				byParent[*task.ParentID] = append(byParent[*task.ParentID], task)
+			}
+			return byParent, nil
+		}),
+		projects: newLoader(func(ctx context.Context, projectIDs []int) (map[int]service.ProjectOut, error) {
+			projects, err := services.Projects.GetProjectsByIDs(ctx, userID(ctx), projectIDs)
+			if err != nil {
+				return nil, err
+			}
+			byID := make(map[int]service.ProjectOut, len(projects))
+			for _, project := range projects {
+				byID[project.ID] = project
+			}
+			return byID, nil
+		}),
+		projectTasks: newLoader(func(ctx context.Context, keys []projectTasksKey) (map[projectTasksKey]service.TaskList, error) {
+			// the projects asked for with the same filter are listed together
+			byFilter := make(map[string][]int)
+			for _, key := range keys {
+				byFilter[key.filter] = append(byFilter[key.filter], key.projectID)
+			}
+			lists := make(map[projectTasksKey]service.TaskList, len(keys))
+			for filter, projectIDs := range byFilter {
+				var input service.TaskListInput
+				if err := json.Unmarshal([]byte(filter), &input); err != nil {
+					return nil, err
+				}
+				byProject, err := services.Tasks.GetTasksOfProjects(ctx, userID(ctx), projectIDs, input)
+				if err != nil {
+					return nil, err
+				}
+				for projectID, list := range byProject {
+					lists[projectTasksKey{projectID: projectID, filter: filter}] = list
+				}
+			}
+			return lists, nil
+		}),
+	}
+}
+
+// forget drops what was loaded before a mutation, which may have changed
+// any of it: the task itself, its parent, the tasks it blocks or the lists
+// of its project.
+func (l *loaders) forget() {
+	l.tasks.forget()
+	l.subtasks.forget()
+	l.projects.forget()
+	l.projectTasks.forget()
+}
+
+type loadersKey struct{}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,205 @@
+package graphql
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/yosakoo/task-traker/internal/service"
+)
+
+// Resolver resolves queries and mutations.
+type Resolver struct {
+	services *service.Services
+}
+
+func (r *Resolver) Me(ctx context.Context) (*userResolver, error) {
+	user, err := r.services.Users.GetUserByID(ctx, userID(ctx))
+	if err != nil {
+		return nil, toResolverError(err)
+	}
+	return &userResolver{r: r, user: user}, nil
+}
+
+func (r *Resolver) Task(ctx context.Context, args struct{ ID graphql.ID }) (*taskResolver, error) {
+	taskID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	task, err := r.services.Tasks.GetTaskByID(ctx, userID(ctx), taskID)
+	if err != nil {
+		return nil, toResolverError(err)
+	}
+	return r.tasks(ctx, []service.TaskOut{task})[0], nil
+}
+
+func (r *Resolver) Tasks(ctx context.Context, args struct{ Filter *taskFilter }) (*taskListResolver, error) {
+	input, err := args.Filter.input()
+	if err != nil {
+		return nil, err
+	}
+	list, err := r.services.Tasks.GetUserTasks(ctx, userID(ctx), input)
+	if err != nil {
+		return nil, toResolverError(err)
+	}
+	return r.taskList(ctx, list), nil
+}
+
+func (r *Resolver) Project(ctx context.Context, args struct{ ID graphql.ID }) (*projectResolver, error) {
+	projectID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	project, err := r.services.Projects.GetProjectByID(ctx, userID(ctx), projectID)
+	if err != nil {
+		return nil, toResolverError(err)
+	}
+	return &projectResolver{r: r, project: project}, nil
+}
+
+func (r *Resolver) Projects(ctx context.Context) ([]*projectResolver, error) {
+	projects, err := r.services.Projects.GetUserProjects(ctx, userID(ctx))
+	if err != nil {
+		return nil, toResolverError(err)
+	}
+	return r.projects(projects), nil
+}
+
+func (r *Resolver) Tags(ctx context.Context) ([]*tagResolver, error) {
+	tags, err := r.services.Tags.GetUserTags(ctx, userID(ctx))
+	if err != nil {
+		return nil, toResolverError(err)
+	}
+	return tagResolvers(tags), nil
+}
+
+func (r *Resolver) CreateTask(ctx context.Context, args struct{ Input taskInput }) (*taskResolver, error) {
+	input, err := args.Input.input()
+	if err != nil {
+		return nil, err
+	}
+	taskID, err := r.services.Tasks.CreateTask(ctx, userID(ctx), input)
+	if err != nil {
+		return nil, toResolverError(err)
+	}
+	loadersFrom(ctx).forget()
+	return r.Task(ctx, struct{ ID graphql.ID }{formatID(taskID)})
+}
+
+func (r *Resolver) UpdateTask(ctx context.Context, args struct {
+	ID      graphql.ID
+	Input   taskInput
+	Version *int32
+	Force   *bool
+}) (*taskResolver, error) {
+	taskID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	input, err := args.Input.input()
+	if err != nil {
+		return nil, err
+	}
+	if args.Version != nil {
+		input.Version = int(*args.Version)
+	}
+	if args.Force != nil {
+		input.Force = *args.Force
+	}
+
+	if err := r.services.Tasks.UpdateTask(ctx, userID(ctx), taskID, input); err != nil {
+		return nil, toResolverError(err)
+	}
+	loadersFrom(ctx).forget()
+	return r.Task(ctx, struct{ ID graphql.ID }{args.ID})
+}
+
+func (r *Resolver) DeleteTask(ctx context.Context, args struct {
+	ID      graphql.ID
+	Version *int32
+}) (graphql.ID, error) {
+	taskID, err := parseID(args.ID)
+	if err != nil {
+		return "", err
+	}
+	var version int
+	if args.Version != nil {
+		version = int(*args.Version)
+	}
+
+	if err := r.services.Tasks.DeleteTask(ctx, userID(ctx), taskID, version); err != nil {
+		return "", toResolverError(err)
+	}
+	loadersFrom(ctx).forget()
+	return args.ID, nil
+}
+
+// tasks wraps tasks for resolving. What they reference is queued to be
+// loaded in one batch once a field asks for it.
+func (r *Resolver) tasks(ctx context.Context, tasks []service.TaskOut) []*taskResolver {
+	l := loadersFrom(ctx)
+
+	resolvers := make([]*taskResolver, 0, len(tasks))
+	for _, task := range tasks {
+		l.tasks.prime(task.ID, task)
+		l.subtasks.prefetch(task.ID)
+		if task.ProjectID != nil {
+			l.projects.prefetch(*task.ProjectID)
+		}
+		if task.ParentID != nil {
+			l.tasks.prefetch(*task.ParentID)
+		}
+		l.tasks.prefetch(task.BlockedBy...)
+		l.tasks.prefetch(task.Blocking...)
+
+		resolvers = append(resolvers, &taskResolver{r: r, task: task})
+	}
+	return resolvers
+}
+
+func (r *Resolver) taskList(ctx context.Context, list service.TaskList) *taskListResolver {
+	groups := make([]*taskGroupResolver, 0, len(list.Groups))
+	for _, group := range list.Groups {
+		groups = append(groups, &taskGroupResolver{
+			group: group,
+			tasks: r.tasks(ctx, group.Tasks),
+		})
+	}
+	return &taskListResolver{groups: groups, nextCursor: list.NextCursor}
+}
+
+func (r *Resolver) projects(projects []service.ProjectOut) []*projectResolver {
+	projectIDs := make([]int, 0, len(projects))
+	for _, project := range projects {
+		projectIDs = append(projectIDs, project.ID)
+	}
+	resolvers := make([]*projectResolver, 0, len(projects))
+	for _, project := range projects {
+		resolvers = append(resolvers, &projectResolver{r: r, project: project, siblings: projectIDs})
+	}
+	return resolvers
+}
+
+func parseID(id graphql.ID) (int, error) {
+	n, err := strconv.Atoi(string(id))
+	if err != nil {
+		return 0, badInput("invalid ID")
+	}
+	return n, nil
+}
+
+func parseOptionalID(id *graphql.ID) (*int, error) {
+	if id == nil {
+		return nil, nil
+	}
+	n, err := parseID(*id)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func formatID(id int) graphql.ID {
+	return graphql.ID(strconv.Itoa(id))
+}
@@ -0,0 +1,304 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/yosakoo/task-traker/internal/service"
+)
+
+type userResolver struct {
+	r    *Resolver
+	user service.AuthUser
+}
+
+func (u *userResolver) ID() graphql.ID { return formatID(u.user.ID) }
+func (u *userResolver) Name() string   { return u.user.Name }
+func (u *userResolver) Email() string  { return u.user.Email }
+func (u *userResolver) VerifiedAt() *graphql.Time {
+	return optionalTime(u.user.VerifiedAt)
+}
+
+func (u *userResolver) Tasks(ctx context.Context, args struct{ Filter *taskFilter }) (*taskListResolver, error) {
+	return u.r.Tasks(ctx, args)
+}
+
+func (u *userResolver) Projects(ctx context.Context) ([]*projectResolver, error) {
+	return u.r.Projects(ctx)
+}
+
+func (u *userResolver) Tags(ctx context.Context) ([]*tagResolver, error) {
+	return u.r.Tags(ctx)
+}
+
+type taskResolver struct {
+	r    *Resolver
+	task service.TaskOut
+}
+
+func (t *taskResolver) ID() graphql.ID       { return formatID(t.task.ID) }
+func (t *taskResolver) Title() string        { return t.task.Title }
+func (t *taskResolver) Text() string         { return t.task.Text }
+func (t *taskResolver) Status() string       { return t.task.Status }
+func (t *taskResolver) Priority() string     { return t.task.Priority }
+func (t *taskResolver) DueAt() *graphql.Time { return optionalTime(t.task.DueAt) }
+func (t *taskResolver) IsOverdue() bool      { return t.task.IsOverdue }
+func (t *taskResolver) CompletedAt() *graphql.Time {
+	return optionalTime(t.task.CompletedAt)
+}
+func (t *taskResolver) CreatedAt() graphql.Time { return graphql.Time{Time: t.task.CreatedAt} }
+func (t *taskResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: t.task.UpdatedAt} }
+func (t *taskResolver) AutoComplete() bool      { return t.task.AutoComplete }
+func (t *taskResolver) Occurrence() int32       { return int32(t.task.Occurrence) }
+func (t *taskResolver) Version() int32          { return int32(t.task.Version) }
+
+func (t *taskResolver) Progress() *progressResolver {
+	return &progressResolver{progress: t.task.Progress}
+}
+
+func (t *taskResolver) Recurrence() *string {
+	if t.task.Recurrence == "" {
+		return nil
+	}
+	return &t.task.Recurrence
+}
+
+func (t *taskResolver) Tags() []*tagResolver {
+	return tagResolvers(t.task.Tags)
+}
+
+func (t *taskResolver) Project(ctx context.Context) (*projectResolver, error) {
+	if t.task.ProjectID == nil {
+		return nil, nil
+	}
+	project, found, err := loadersFrom(ctx).projects.load(ctx, *t.task.ProjectID)
+	if err != nil {
+		return nil, toResolverError(err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &projectResolver{r: t.r, project: project}, nil
+}
+
+func (t *taskResolver) Parent(ctx context.Context) (*taskResolver, error) {
+	if t.task.ParentID == nil {
+		return nil, nil
+	}
+	parent, found, err := loadersFrom(ctx).tasks.load(ctx, *t.task.ParentID)
+	if err != nil {
+		return nil, toResolverError(err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return t.r.tasks(ctx, []service.TaskOut{parent})[0], nil
+}
+
+func (t *taskResolver) Subtasks(ctx context.Context) ([]*taskResolver, error) {
+	subtasks, _, err := loadersFrom(ctx).subtasks.load(ctx, t.task.ID)
+	if err != nil {
+		return nil, toResolverError(err)
+	}
+	return t.r.tasks(ctx, subtasks), nil
+}
+
+func (t *taskResolver) BlockedBy(ctx context.Context) ([]*taskResolver, error) {
+	return t.loadTasks(ctx, t.task.BlockedBy)
+}
+
+func (t *taskResolver) Blocking(ctx context.Context) ([]*taskResolver, error) {
+	return t.loadTasks(ctx, t.task.Blocking)
+}
+
+func (t *taskResolver) loadTasks(ctx context.Context, taskIDs []int) ([]*taskResolver, error) {
+	tasks, err := loadersFrom(ctx).tasks.loadMany(ctx, taskIDs)
+	if err != nil {
+		return nil, toResolverError(err)
+	}
+	return t.r.tasks(ctx, tasks), nil
+}
+
+type progressResolver struct {
+	progress service.ProgressOut
+}
+
+func (p *progressResolver) Done() int32  { return int32(p.progress.Done) }
+func (p *progressResolver) Total() int32 { return int32(p.progress.Total) }
+func (p *progressResolver) Text() string { return p.progress.Text }
+
+type taskListResolver struct {
+	groups     []*taskGroupResolver
+	nextCursor string
+}
+
+func (l *taskListResolver) Groups() []*taskGroupResolver { return l.groups }
+
+func (l *taskListResolver) NextCursor() *string {
+	if l.nextCursor == "" {
+		return nil
+	}
+	return &l.nextCursor
+}
+
+type taskGroupResolver struct {
+	group service.TaskGroup
+	tasks []*taskResolver
+}
+
+func (g *taskGroupResolver) Status() string         { return g.group.Status }
+func (g *taskGroupResolver) Category() string       { return g.group.Category }
+func (g *taskGroupResolver) Tasks() []*taskResolver { return g.tasks }
+
+type projectResolver struct {
+	r       *Resolver
+	project service.ProjectOut
+	// siblings are the IDs of the projects listed along with this one,
+	// their tasks are loaded in one batch.
+	siblings []int
+}
+
+func (p *projectResolver) ID() graphql.ID          { return formatID(p.project.ID) }
+func (p *projectResolver) Name() string            { return p.project.Name }
+func (p *projectResolver) Description() string     { return p.project.Description }
+func (p *projectResolver) CreatedAt() graphql.Time { return graphql.Time{Time: p.project.CreatedAt} }
+func (p *projectResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: p.project.UpdatedAt} }
+
+func (p *projectResolver) Tasks(ctx context.Context, args struct{ Filter *taskFilter }) (*taskListResolver, error) {
+	input, err := args.Filter.input()
+	if err != nil {
+		return nil, err
+	}
+	l := loadersFrom(ctx).projectTasks
+	for _, projectID := range p.siblings {
+		key, err := newProjectTasksKey(projectID, input)
+		if err != nil {
+			return nil, err
+		}
+		l.prefetch(key)
+	}
+	key, err := newProjectTasksKey(p.project.ID, input)
+	if err != nil {
+		return nil, err
+	}
+	list, _, err := l.load(ctx, key)
+	if err != nil {
+		return nil, toResolverError(err)
+	}
+	return p.r.taskList(ctx, list), nil
+}
+
+type tagResolver struct {
+	tag service.TagOut
+}
+
+func (t *tagResolver) ID() graphql.ID { return formatID(t.tag.ID) }
+func (t *tagResolver) Name() string   { return t.tag.Name }
+func (t *tagResolver) Color() string  { return t.tag.Color }
+
+func tagResolvers(tags []service.TagOut) []*tagResolver {
+	resolvers := make([]*tagResolver, 0, len(tags))
+	for _, tag := range tags {
+		resolvers = append(resolvers, &tagResolver{tag: tag})
+	}
+	return resolvers
+}
+
+type taskFilter struct {
+	ProjectID *graphql.ID
+	Status    *string
+	Query     *string
+	Tags      *[]string
+	TagMode   *string
+	Overdue   *bool
+	Sort      *string
+	Order     *string
+	Limit     *int32
+	Cursor    *string
+}
+
+func (f *taskFilter) input() (service.TaskListInput, error) {
+	if f == nil {
+		return service.TaskListInput{}, nil
+	}
+	projectID, err := parseOptionalID(f.ProjectID)
+	if err != nil {
+		return service.TaskListInput{}, err
+	}
+
+	input := service.TaskListInput{
+		ProjectID: projectID,
+		Status:    value(f.Status),
+		Search:    value(f.Query),
+		TagMatch:  value(f.TagMode),
+		Overdue:   value(f.Overdue),
+		SortBy:    value(f.Sort),
+		Order:     value(f.Order),
+		Cursor:    value(f.Cursor),
+	}
+	if f.Tags != nil {
+		input.Tags = *f.Tags
+	}
+	if f.Limit != nil {
+		input.Limit = int(*f.Limit)
+	}
+	return input, nil
+}
+
+type taskInput struct {
+	ProjectID    *graphql.ID
+	ParentID     *graphql.ID
+	Title        string
+	Status       *string
+	Text         *string
+	Priority     *string
+	DueAt        *graphql.Time
+	AutoComplete *bool
+	Recurrence   *string
+}
+
+func (i taskInput) input() (service.TaskInput, error) {
+	if i.Title == "" {
+		return service.TaskInput{}, badInput("task title is required")
+	}
+	projectID, err := parseOptionalID(i.ProjectID)
+	if err != nil {
+		return service.TaskInput{}, err
+	}
+	parentID, err := parseOptionalID(i.ParentID)
+	if err != nil {
+		return service.TaskInput{}, err
+	}
+
+	input := service.TaskInput{
+		ProjectID:    projectID,
+		ParentID:     parentID,
+		Title:        i.Title,
+		Status:       value(i.Status),
+		Text:         value(i.Text),
+		Priority:     value(i.Priority),
+		AutoComplete: i.AutoComplete,
+		Recurrence:   i.Recurrence,
+	}
+	if i.DueAt != nil {
+		input.DueAt = &i.DueAt.Time
+	}
+	return input, nil
+}
+
+func optionalTime(t *time.Time) *graphql.Time {
+	if t == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *t}
+}
+
+func value[T any](p *T) T {
+	var v T
+	if p != nil {
+		v = *p
+	}
+	return v
+}
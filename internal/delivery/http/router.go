@@ -2,7 +2,9 @@ package http
 
 import (
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/rs/cors"
+	"github.com/yosakoo/task-traker/internal/delivery/http/graphql"
 	"github.com/yosakoo/task-traker/internal/delivery/http/v1"
 	"github.com/yosakoo/task-traker/internal/service"
 	"github.com/yosakoo/task-traker/pkg/auth"
@@ -13,12 +15,14 @@ import (
 type Handler struct {
 	services     *service.Services
 	tokenManager auth.TokenManager
+	options      v1.Options
 }
 
-func NewHandler(services *service.Services, tokenManager auth.TokenManager) *Handler {
+func NewHandler(services *service.Services, tokenManager auth.TokenManager, options v1.Options) *Handler {
 	return &Handler{
 		services:     services,
 		tokenManager: tokenManager,
+		options:      options,
 	}
 }
 
@@ -27,24 +31,27 @@ func (h *Handler) Init(l logger.Interface) *chi.Mux {
 
 	c := cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "If-Match", "If-None-Match", "Idempotency-Key"},
+		ExposedHeaders:   []string{"ETag", "Idempotent-Replayed"},
 		AllowCredentials: true,
 		Debug:            true,
 	})
 	router.Use(c.Handler)
+	router.Use(middleware.RequestID)
 	router.Use(v1.NewMwLogger(l))
 	router.Get("/ping", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("pong"))
 	})
-	h.initAPI(router)
+	h.initAPI(router, l)
 	return router
 }
 
-func (h *Handler) initAPI(router chi.Router) {
-	handlerV1 := v1.NewHandler(h.services, h.tokenManager)
+func (h *Handler) initAPI(router chi.Router, l logger.Interface) {
+	handlerV1 := v1.NewHandler(h.services, h.tokenManager, l, h.options)
 	router.Route("/api", func(api chi.Router) {
 		handlerV1.Init(api)
+		api.With(handlerV1.AuthMiddleware).Post("/graphql", graphql.NewHandler(h.services).ServeHTTP)
 	})
 }
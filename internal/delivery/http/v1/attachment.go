@@ -0,0 +1,142 @@
+package v1
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/yosakoo/task-traker/internal/service"
+)
+
+func (h *Handler) getAttachments(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	attachments, err := h.services.Attachments.GetAttachments(r.Context(), userId, taskID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	jsonResponse, err := json.Marshal(attachments)
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonResponse)
+}
+
+// uploadAttachment streams the "file" part of a multipart form to the blob
+// store, the file is never held in memory as a whole.
+func (h *Handler) uploadAttachment(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "expected a multipart form")
+		return
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			writeProblem(w, r, http.StatusBadRequest, "file is missing")
+			return
+		}
+		if err != nil {
+			writeProblem(w, r, http.StatusBadRequest, "invalid multipart form")
+			return
+		}
+		if part.FormName() != "file" || part.FileName() == "" {
+			part.Close()
+			continue
+		}
+
+		userId := r.Context().Value("user_id").(int)
+		attachment, err := h.services.Attachments.UploadAttachment(r.Context(), userId, taskID, service.AttachmentInput{
+			Filename:    part.FileName(),
+			ContentType: part.Header.Get("Content-Type"),
+			Body:        part,
+		})
+		part.Close()
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		jsonResponse, err := json.Marshal(attachment)
+		if err != nil {
+			writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(jsonResponse)
+		return
+	}
+}
+
+func (h *Handler) downloadAttachment(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+	attachmentID, err := strconv.Atoi(chi.URLParam(r, "attachmentID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid attachment ID")
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	attachment, body, err := h.services.Attachments.OpenAttachment(r.Context(), userId, taskID, attachmentID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, body)
+}
+
+func (h *Handler) deleteAttachment(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+	attachmentID, err := strconv.Atoi(chi.URLParam(r, "attachmentID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid attachment ID")
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	err = h.services.Attachments.DeleteAttachment(r.Context(), userId, taskID, attachmentID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
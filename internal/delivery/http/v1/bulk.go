@@ -0,0 +1,73 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/yosakoo/task-traker/internal/service"
+)
+
+type bulkOperationInput struct {
+	Op        string `json:"op" validate:"required,oneof=update_status set_tags move_project delete"`
+	TaskIDs   []int  `json:"task_ids" validate:"required,min=1"`
+	Status    string `json:"status" validate:"required_if=Op update_status"`
+	Force     bool   `json:"force"`
+	TagIDs    []int  `json:"tag_ids"`
+	ProjectID *int   `json:"project_id"`
+}
+
+type bulkInput struct {
+	Operations []bulkOperationInput `json:"operations" validate:"required,min=1,dive"`
+	// Atomic applies either every item or none of them, by default every
+	// item that can be applied is.
+	Atomic bool `json:"atomic"`
+}
+
+type bulkResponse struct {
+	Results []service.BulkItemResult `json:"results"`
+}
+
+func (h *Handler) bulkUpdateTasks(w http.ResponseWriter, r *http.Request) {
+	var input bulkInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := h.validate.Struct(input); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	operations := make([]service.BulkOperation, 0, len(input.Operations))
+	for _, op := range input.Operations {
+		operations = append(operations, service.BulkOperation{
+			Op:        op.Op,
+			TaskIDs:   op.TaskIDs,
+			Status:    op.Status,
+			Force:     op.Force,
+			TagIDs:    op.TagIDs,
+			ProjectID: op.ProjectID,
+		})
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	results, err := h.services.Tasks.BulkUpdate(r.Context(), userId, service.BulkInput{
+		Operations: operations,
+		Atomic:     input.Atomic,
+	})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	jsonResponse, err := json.Marshal(bulkResponse{Results: results})
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonResponse)
+}
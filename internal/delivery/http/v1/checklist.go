@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/yosakoo/task-traker/internal/service"
+)
+
+type checklistItemInput struct {
+	Text string `json:"text" validate:"required"`
+	Done bool   `json:"done"`
+}
+
+type checklistItemUpdateInput struct {
+	Text     *string `json:"text" validate:"omitempty,min=1"`
+	Done     *bool   `json:"done"`
+	Position *int    `json:"position" validate:"omitempty,min=0"`
+}
+
+func (h *Handler) getChecklist(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	items, err := h.services.Checklists.GetItems(r.Context(), userId, taskID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	jsonResponse, err := json.Marshal(items)
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonResponse)
+}
+
+func (h *Handler) addChecklistItem(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+
+	var input checklistItemInput
+	err = json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := h.validate.Struct(input); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	itemID, err := h.services.Checklists.AddItem(r.Context(), userId, taskID, service.ChecklistItemInput{
+		Text: input.Text,
+		Done: input.Done,
+	})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(strconv.Itoa(itemID)))
+}
+
+func (h *Handler) updateChecklistItem(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+	itemID, err := strconv.Atoi(chi.URLParam(r, "itemID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid checklist item ID")
+		return
+	}
+
+	var input checklistItemUpdateInput
+	err = json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := h.validate.Struct(input); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	err = h.services.Checklists.UpdateItem(r.Context(), userId, taskID, itemID, service.ChecklistItemUpdateInput{
+		Text:     input.Text,
+		Done:     input.Done,
+		Position: input.Position,
+	})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) deleteChecklistItem(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+	itemID, err := strconv.Atoi(chi.URLParam(r, "itemID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid checklist item ID")
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	err = h.services.Checklists.DeleteItem(r.Context(), userId, taskID, itemID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,156 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/yosakoo/task-traker/internal/service"
+)
+
+type commentInput struct {
+	Text string `json:"text" validate:"required"`
+}
+
+type getCommentsResponse struct {
+	Comments   []service.CommentOut `json:"comments"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+func writeComment(w http.ResponseWriter, r *http.Request, status int, comment service.CommentOut) {
+	jsonResponse, err := json.Marshal(comment)
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(jsonResponse)
+}
+
+func (h *Handler) getComments(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+
+	input := service.CommentListInput{Cursor: r.URL.Query().Get("cursor")}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		input.Limit, err = strconv.Atoi(limit)
+		if err != nil || input.Limit <= 0 {
+			writeProblem(w, r, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	list, err := h.services.Comments.GetComments(r.Context(), userId, taskID, input)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	jsonResponse, err := json.Marshal(getCommentsResponse{
+		Comments:   list.Comments,
+		NextCursor: list.NextCursor,
+	})
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonResponse)
+}
+
+func (h *Handler) createComment(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+
+	var input commentInput
+	err = json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := h.validate.Struct(input); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	comment, err := h.services.Comments.CreateComment(r.Context(), userId, taskID, service.CommentInput{
+		Text: input.Text,
+	})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	writeComment(w, r, http.StatusCreated, comment)
+}
+
+func (h *Handler) updateComment(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+	commentID, err := strconv.Atoi(chi.URLParam(r, "commentID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid comment ID")
+		return
+	}
+
+	var input commentInput
+	err = json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := h.validate.Struct(input); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	comment, err := h.services.Comments.UpdateComment(r.Context(), userId, taskID, commentID, service.CommentInput{
+		Text: input.Text,
+	})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	writeComment(w, r, http.StatusOK, comment)
+}
+
+func (h *Handler) deleteComment(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+	commentID, err := strconv.Atoi(chi.URLParam(r, "commentID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid comment ID")
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	err = h.services.Comments.DeleteComment(r.Context(), userId, taskID, commentID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
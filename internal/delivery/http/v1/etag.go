@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	errPreconditionRequired = errors.New("If-Match header is required")
+	errInvalidIfMatch       = errors.New("invalid If-Match header")
+)
+
+func taskETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// writeTaskETag answers a change of a task that has no body with the ETag of
+// the task as it is now.
+func (h *Handler) writeTaskETag(w http.ResponseWriter, r *http.Request, userId, taskID int) {
+	task, err := h.services.Tasks.GetTaskByID(r.Context(), userId, taskID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", taskETag(task.Version))
+	w.WriteHeader(http.StatusOK)
+}
+
+// ifMatchVersion returns the task version an If-Match header asks for,
+// 0 when any version will do.
+func (h *Handler) ifMatchVersion(r *http.Request) (int, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" {
+		if h.options.RequireIfMatch {
+			return 0, errPreconditionRequired
+		}
+		return 0, nil
+	}
+	if value == "*" {
+		return 0, nil
+	}
+	// only a single strong tag is accepted, weak tags never match for If-Match
+	if len(value) < 2 || !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`) {
+		return 0, errInvalidIfMatch
+	}
+	version, err := strconv.Atoi(value[1 : len(value)-1])
+	if err != nil || version <= 0 {
+		return 0, errInvalidIfMatch
+	}
+	return version, nil
+}
+
+// noneMatch reports whether an If-None-Match header matches etag, using the
+// weak comparison RFC 9110 asks for.
+func noneMatch(r *http.Request, etag string) bool {
+	value := r.Header.Get("If-None-Match")
+	if value == "" {
+		return false
+	}
+	for _, tag := range strings.Split(value, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -6,20 +6,34 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/yosakoo/task-traker/internal/service"
 	"github.com/yosakoo/task-traker/pkg/auth"
+	"github.com/yosakoo/task-traker/pkg/logger"
 	"github.com/go-playground/validator/v10"
 )
 
+// Options tune how the API behaves.
+type Options struct {
+	// RequireIfMatch rejects task changes that don't say which version they change.
+	RequireIfMatch bool
+}
+
 type Handler struct {
 	services     *service.Services
 	tokenManager auth.TokenManager
+	log          logger.Interface
 	validate     *validator.Validate
+	options      Options
 }
 
-func NewHandler(services *service.Services, tokenManager auth.TokenManager) *Handler {
+func NewHandler(services *service.Services, tokenManager auth.TokenManager, log logger.Interface, options Options) *Handler {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(jsonFieldName)
+
 	return &Handler{
-		validate:     validator.New(),
+		validate:     validate,
 		services:     services,
 		tokenManager: tokenManager,
+		log:          log,
+		options:      options,
 	}
 }
 
@@ -27,5 +41,10 @@ func (h *Handler) Init(router chi.Router) {
     router.Group(func(v1 chi.Router) {
         h.initUsersRoutes(v1)
 		h.initTasksRoutes(v1)
+		h.initProjectsRoutes(v1)
+		h.initTagsRoutes(v1)
+		h.initWorkflowRoutes(v1)
+		h.initTrashRoutes(v1)
+		h.initDocsRoutes(v1)
     })
 }
\ No newline at end of file
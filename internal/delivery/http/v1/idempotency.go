@@ -0,0 +1,173 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/yosakoo/task-traker/internal/service"
+)
+
+const (
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBodySize bounds request bodies read ahead to fingerprint them.
+	maxIdempotentBodySize = 32 << 20
+	// maxBufferedBodySize is how much of a body is kept in memory, larger
+	// bodies like attachment uploads are spooled to a temporary file.
+	maxBufferedBodySize = 64 << 10
+)
+
+// replayedHeaders are the response headers stored along with the body.
+var replayedHeaders = []string{"Content-Type", "Content-Disposition", "ETag", "Location", "Accept-Patch"}
+
+// IdempotencyMiddleware makes POST, PUT, PATCH and DELETE requests that carry
+// an Idempotency-Key safe to retry: the first response is stored and replayed
+// for later requests with the same key. It must run after AuthMiddleware, so
+// that keys are scoped to the user; requests without a user are passed on
+// untouched.
+func (h *Handler) IdempotencyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		userId, authenticated := r.Context().Value("user_id").(int)
+		if key == "" || !isUnsafeMethod(r.Method) || !authenticated {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			writeProblem(w, r, http.StatusBadRequest, "invalid idempotency key")
+			return
+		}
+
+		body, fingerprint, err := readBodyAhead(w, r)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				writeProblem(w, r, http.StatusRequestEntityTooLarge, "request body is too large")
+				return
+			}
+			writeProblem(w, r, http.StatusBadRequest, "could not read request body")
+			return
+		}
+		defer body.Close()
+		r.Body = body
+
+		stored, err := h.services.Idempotency.Begin(r.Context(), userId, key, fingerprint)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		if stored != nil {
+			for name, values := range stored.Headers {
+				w.Header()[name] = values
+			}
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(stored.StatusCode)
+			w.Write(stored.Body)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		// the response is out already, so the key is settled even if the
+		// client went away meanwhile
+		ctx := context.WithoutCancel(r.Context())
+		if rec.status == 0 || rec.status >= http.StatusInternalServerError {
+			h.services.Idempotency.Release(ctx, userId, key)
+			return
+		}
+
+		headers := make(map[string][]string)
+		for _, name := range replayedHeaders {
+			if values := w.Header().Values(name); len(values) > 0 {
+				headers[name] = values
+			}
+		}
+		err = h.services.Idempotency.Finish(ctx, userId, key, service.StoredResponse{
+			StatusCode: rec.status,
+			Headers:    headers,
+			Body:       rec.body.Bytes(),
+		})
+		if err != nil {
+			h.services.Idempotency.Release(ctx, userId, key)
+		}
+	})
+}
+
+func isUnsafeMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// readBodyAhead reads the body of r before it is handled and returns a copy
+// of it along with the fingerprint of the request, a hash of its method, URL
+// and body. Only small bodies are held in memory.
+func readBodyAhead(w http.ResponseWriter, r *http.Request) (io.ReadCloser, string, error) {
+	sum := sha256.New()
+	io.WriteString(sum, r.Method+" "+r.URL.RequestURI()+"\n")
+	body := io.TeeReader(http.MaxBytesReader(w, r.Body, maxIdempotentBodySize), sum)
+
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, body, maxBufferedBodySize); err == io.EOF {
+		return io.NopCloser(&buf), hex.EncodeToString(sum.Sum(nil)), nil
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	file, err := os.CreateTemp("", "request-body-")
+	if err != nil {
+		return nil, "", err
+	}
+	spooled := spooledBody{file}
+	if _, err := io.Copy(file, io.MultiReader(&buf, body)); err != nil {
+		spooled.Close()
+		return nil, "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		spooled.Close()
+		return nil, "", err
+	}
+	return spooled, hex.EncodeToString(sum.Sum(nil)), nil
+}
+
+// spooledBody is a request body read ahead into a temporary file, the file
+// is removed when the body is closed.
+type spooledBody struct {
+	*os.File
+}
+
+func (b spooledBody) Close() error {
+	err := b.File.Close()
+	os.Remove(b.Name())
+	return err
+}
+
+// responseRecorder passes a response through and keeps a copy of it.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
@@ -43,22 +43,19 @@ func (h *Handler) AuthMiddleware(next http.Handler) http.Handler {
         }
         
         if accessToken == "" {
-            w.WriteHeader(http.StatusUnauthorized)
-            w.Write([]byte("not authenticated"))
+            writeProblem(w, r, http.StatusUnauthorized, "not authenticated")
             return
         }
 
         userIdStr, err := h.tokenManager.Parse(accessToken)
         if err != nil {
-            w.WriteHeader(http.StatusUnauthorized)
-            w.Write([]byte("not authenticated"))
+            writeProblem(w, r, http.StatusUnauthorized, "not authenticated")
             return
         }
 
         userId, err := strconv.Atoi(userIdStr)
         if err != nil {
-            w.WriteHeader(http.StatusInternalServerError)
-            w.Write([]byte("internal server error"))
+            writeProblem(w, r, http.StatusInternalServerError, "internal server error")
             return
         }
 
@@ -0,0 +1,40 @@
+package v1
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// openAPISpec describes every route of the API. Keep it in sync when adding
+// routes, TestOpenAPICoversRoutes fails otherwise.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// docsPage renders openapi.json as an API reference without loading
+// anything from elsewhere.
+//
+//go:embed docs.html
+var docsPage []byte
+
+func (h *Handler) initDocsRoutes(router chi.Router) {
+	router.Get("/openapi.json", h.getOpenAPISpec)
+	router.Get("/docs", h.getDocs)
+}
+
+func (h *Handler) getOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(openAPISpec)
+}
+
+func (h *Handler) getDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	// the page is self-contained, it may only fetch the spec from this server
+	w.Header().Set("Content-Security-Policy",
+		"default-src 'none'; script-src 'unsafe-inline'; style-src 'unsafe-inline'; connect-src 'self'")
+	w.WriteHeader(http.StatusOK)
+	w.Write(docsPage)
+}
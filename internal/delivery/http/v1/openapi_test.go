@@ -0,0 +1,50 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TestOpenAPICoversRoutes checks that openapi.json describes exactly the
+// routes the handler registers.
+func TestOpenAPICoversRoutes(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		t.Fatalf("parse openapi.json: %v", err)
+	}
+
+	router := chi.NewRouter()
+	NewHandler(nil, nil, nil, Options{}).Init(router)
+
+	registered := make(map[string]bool)
+	err := chi.Walk(router, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		// routes registered as "/" inside a subrouter come out with a trailing slash
+		if route != "/" {
+			route = strings.TrimSuffix(route, "/")
+		}
+		op := strings.ToLower(method) + " " + route
+		registered[op] = true
+
+		if _, ok := spec.Paths[route][strings.ToLower(method)]; !ok {
+			t.Errorf("route %s %s has no entry in openapi.json", method, route)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walk routes: %v", err)
+	}
+
+	for path, ops := range spec.Paths {
+		for method := range ops {
+			if !registered[method+" "+path] {
+				t.Errorf("openapi.json describes %s %s, which isn't registered", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/yosakoo/task-traker/internal/domain/models"
+	"github.com/yosakoo/task-traker/internal/service"
+)
+
+const mergePatchType = "application/merge-patch+json"
+
+// patchField reads one member of a JSON Merge Patch, null clears the field.
+func patchField[T any](doc map[string]json.RawMessage, name string, nullable bool) (models.Nullable[T], error) {
+	raw, ok := doc[name]
+	if !ok {
+		return models.Nullable[T]{}, nil
+	}
+	delete(doc, name)
+	if bytes.Equal(raw, []byte("null")) {
+		if !nullable {
+			return models.Nullable[T]{}, fmt.Errorf("%s can't be null", name)
+		}
+		return models.Nullable[T]{Set: true}, nil
+	}
+	var v T
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return models.Nullable[T]{}, fmt.Errorf("invalid %s", name)
+	}
+	return models.Update(v), nil
+}
+
+// parseTaskPatch turns a JSON Merge Patch (RFC 7396) of a task into the
+// fields it changes. Members the task doesn't have are rejected.
+func parseTaskPatch(body []byte) (service.TaskPatchInput, error) {
+	var input service.TaskPatchInput
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(body, &doc); err != nil || doc == nil {
+		return input, fmt.Errorf("patch must be a JSON object")
+	}
+
+	var err error
+	if input.Title, err = patchField[string](doc, "title", false); err != nil {
+		return input, err
+	}
+	if input.Title.Set && *input.Title.Value == "" {
+		return input, fmt.Errorf("title can't be empty")
+	}
+	if input.Status, err = patchField[string](doc, "status", false); err != nil {
+		return input, err
+	}
+	if input.Text, err = patchField[string](doc, "text", true); err != nil {
+		return input, err
+	}
+	if input.Priority, err = patchField[string](doc, "priority", false); err != nil {
+		return input, err
+	}
+	if input.DueAt, err = patchField[time.Time](doc, "due_at", true); err != nil {
+		return input, err
+	}
+	if input.AutoComplete, err = patchField[bool](doc, "auto_complete", false); err != nil {
+		return input, err
+	}
+	if input.Recurrence, err = patchField[string](doc, "recurrence", true); err != nil {
+		return input, err
+	}
+	if input.ProjectID, err = patchField[int](doc, "project_id", true); err != nil {
+		return input, err
+	}
+
+	for name := range doc {
+		return input, fmt.Errorf("unknown field %s", name)
+	}
+	return input, nil
+}
+
+func (h *Handler) patchTask(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (mediaType != mergePatchType && mediaType != "application/json") {
+		w.Header().Set("Accept-Patch", mergePatchType)
+		writeProblem(w, r, http.StatusUnsupportedMediaType, "expected "+mergePatchType)
+		return
+	}
+
+	var body bytes.Buffer
+	if _, err := body.ReadFrom(r.Body); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	input, err := parseTaskPatch(body.Bytes())
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if value := r.URL.Query().Get("force"); value != "" {
+		input.Force, err = strconv.ParseBool(value)
+		if err != nil {
+			writeProblem(w, r, http.StatusBadRequest, "invalid force")
+			return
+		}
+	}
+	if input.Version, err = h.ifMatchVersion(r); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	err = h.services.Tasks.PatchTask(r.Context(), userId, taskID, input)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	task, err := h.services.Tasks.GetTaskByID(r.Context(), userId, taskID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	jsonResponse, err := json.Marshal(task)
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(task.Version))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonResponse)
+}
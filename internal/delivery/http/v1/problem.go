@@ -0,0 +1,214 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/yosakoo/task-traker/internal/domain"
+)
+
+// problemTypeBase prefixes the type URI of problems specific to this API,
+// other problems use about:blank as RFC 7807 suggests.
+const problemTypeBase = "/problems/"
+
+// Problem is an RFC 7807 error response.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError tells which field of a request body failed validation and why.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+type problemType struct {
+	status int
+	name   string
+	title  string
+}
+
+// domainProblems maps errors returned by services to the problems they are
+// reported as. The error text becomes the detail.
+var domainProblems = []struct {
+	err     error
+	problem problemType
+}{
+	{domain.ErrUserNotFound, problemType{http.StatusNotFound, "user-not-found", "User not found"}},
+	{domain.ErrUserAlreadyExists, problemType{http.StatusConflict, "email-taken", "Email is already taken"}},
+	{domain.ErrTokenExpired, problemType{http.StatusUnauthorized, "token-expired", "Token has expired"}},
+	{domain.ErrInvalidResetToken, problemType{http.StatusBadRequest, "invalid-reset-token", "Invalid password reset token"}},
+	{domain.ErrInvalidVerifyToken, problemType{http.StatusBadRequest, "invalid-verification-token", "Invalid email verification token"}},
+	{domain.ErrEmailAlreadyVerified, problemType{http.StatusConflict, "email-already-verified", "Email is already verified"}},
+	{domain.ErrVerificationThrottled, problemType{http.StatusTooManyRequests, "verification-throttled", "Verification email was sent recently"}},
+	{domain.ErrEmailNotVerified, problemType{http.StatusForbidden, "email-not-verified", "Email is not verified"}},
+	{domain.ErrTaskNotFound, problemType{http.StatusNotFound, "task-not-found", "Task not found"}},
+	{domain.ErrTaskForbidden, problemType{http.StatusForbidden, "task-forbidden", "Access to task is forbidden"}},
+	{domain.ErrTaskVersionMismatch, problemType{http.StatusPreconditionFailed, "task-version-mismatch", "Task was changed"}},
+	{domain.ErrInvalidTaskFilter, problemType{http.StatusBadRequest, "invalid-task-filter", "Invalid task filter"}},
+	{domain.ErrInvalidCursor, problemType{http.StatusBadRequest, "invalid-cursor", "Invalid pagination cursor"}},
+	{domain.ErrInvalidStatus, problemType{http.StatusBadRequest, "invalid-status", "Invalid task status"}},
+	{domain.ErrInvalidPriority, problemType{http.StatusBadRequest, "invalid-priority", "Invalid task priority"}},
+	{domain.ErrInvalidRecurrence, problemType{http.StatusBadRequest, "invalid-recurrence", "Invalid recurrence rule"}},
+	{domain.ErrStatusTransition, problemType{http.StatusUnprocessableEntity, "status-transition", "Status transition is not allowed"}},
+	{domain.ErrSubtaskCycle, problemType{http.StatusUnprocessableEntity, "subtask-cycle", "Subtask cycle"}},
+	{domain.ErrSubtaskTooDeep, problemType{http.StatusUnprocessableEntity, "subtask-too-deep", "Subtasks are nested too deep"}},
+	{domain.ErrDependencyCycle, problemType{http.StatusUnprocessableEntity, "dependency-cycle", "Dependency cycle"}},
+	{domain.ErrDependencyNotFound, problemType{http.StatusNotFound, "dependency-not-found", "Dependency not found"}},
+	{domain.ErrTaskBlocked, problemType{http.StatusConflict, "task-blocked", "Task is blocked"}},
+	{domain.ErrOccurrenceExists, problemType{http.StatusConflict, "occurrence-exists", "Occurrence already exists"}},
+	{domain.ErrInvalidBatch, problemType{http.StatusBadRequest, "invalid-batch", "Invalid batch operation"}},
+	{domain.ErrChecklistItemNotFound, problemType{http.StatusNotFound, "checklist-item-not-found", "Checklist item not found"}},
+	{domain.ErrCommentNotFound, problemType{http.StatusNotFound, "comment-not-found", "Comment not found"}},
+	{domain.ErrCommentForbidden, problemType{http.StatusForbidden, "comment-forbidden", "Only the author can change a comment"}},
+	{domain.ErrAttachmentNotFound, problemType{http.StatusNotFound, "attachment-not-found", "Attachment not found"}},
+	{domain.ErrAttachmentTooLarge, problemType{http.StatusRequestEntityTooLarge, "attachment-too-large", "Attachment is too large"}},
+	{domain.ErrAttachmentType, problemType{http.StatusUnsupportedMediaType, "attachment-type", "Attachment type is not allowed"}},
+	{domain.ErrProjectNotFound, problemType{http.StatusNotFound, "project-not-found", "Project not found"}},
+	{domain.ErrProjectForbidden, problemType{http.StatusForbidden, "project-forbidden", "Access to project is forbidden"}},
+	{domain.ErrTagNotFound, problemType{http.StatusNotFound, "tag-not-found", "Tag not found"}},
+	{domain.ErrTagForbidden, problemType{http.StatusForbidden, "tag-forbidden", "Access to tag is forbidden"}},
+	{domain.ErrTagAlreadyExists, problemType{http.StatusConflict, "tag-exists", "Tag already exists"}},
+	{domain.ErrInvalidTagName, problemType{http.StatusBadRequest, "invalid-tag-name", "Invalid tag name"}},
+	{domain.ErrWorkflowNotFound, problemType{http.StatusNotFound, "workflow-not-found", "Workflow not found"}},
+	{domain.ErrInvalidWorkflow, problemType{http.StatusBadRequest, "invalid-workflow", "Invalid workflow"}},
+	{domain.ErrWorkflowStatusInUse, problemType{http.StatusConflict, "workflow-status-in-use", "Workflow status is in use"}},
+	{domain.ErrIdempotencyKeyReused, problemType{http.StatusUnprocessableEntity, "idempotency-key-reused", "Idempotency key reused"}},
+	{domain.ErrIdempotencyKeyInUse, problemType{http.StatusConflict, "idempotency-key-in-use", "Request is in progress"}},
+	{errPreconditionRequired, problemType{http.StatusPreconditionRequired, "if-match-required", "Precondition required"}},
+	{errInvalidIfMatch, problemType{http.StatusBadRequest, "invalid-if-match", "Invalid If-Match header"}},
+}
+
+// writeError answers with the problem err maps to. Errors unknown to the
+// mapping are internal, they are logged and their text isn't shown to the
+// client.
+func (h *Handler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	for _, p := range domainProblems {
+		if errors.Is(err, p.err) {
+			writeProblemJSON(w, r, Problem{
+				Type:   problemTypeBase + p.problem.name,
+				Title:  p.problem.title,
+				Status: p.problem.status,
+				Detail: err.Error(),
+			})
+			return
+		}
+	}
+	h.log.Error(fmt.Errorf("v1 - %s %s: %w", r.Method, r.URL.Path, err))
+	writeProblem(w, r, http.StatusInternalServerError, "")
+}
+
+// writeProblem answers with a problem that is fully described by its status.
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	writeProblemJSON(w, r, Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	})
+}
+
+// writeValidationError answers with the fields of a request body that
+// failed validation.
+func writeValidationError(w http.ResponseWriter, r *http.Request, err error) {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	fields := make([]FieldError, 0, len(errs))
+	for _, fe := range errs {
+		fields = append(fields, FieldError{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Message: fieldMessage(fe),
+		})
+	}
+	writeProblemJSON(w, r, Problem{
+		Type:   problemTypeBase + "validation",
+		Title:  "Invalid request body",
+		Status: http.StatusBadRequest,
+		Detail: "some fields of the request body are invalid",
+		Errors: fields,
+	})
+}
+
+func writeProblemJSON(w http.ResponseWriter, r *http.Request, problem Problem) {
+	problem.Instance = r.URL.Path
+	problem.RequestID = middleware.GetReqID(r.Context())
+
+	jsonResponse, err := json.Marshal(problem)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	w.Write(jsonResponse)
+}
+
+// jsonFieldName makes the validator name fields the way they appear in
+// request bodies.
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// fieldPath returns the path of the field within the request body, e.g.
+// "operations[1].status".
+func fieldPath(fe validator.FieldError) string {
+	_, path, found := strings.Cut(fe.Namespace(), ".")
+	if !found {
+		return fe.Field()
+	}
+	return path
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_if":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "hexcolor":
+		return "must be a hex color"
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "min", "max":
+		bound := "at least"
+		if fe.Tag() == "max" {
+			bound = "at most"
+		}
+		switch fe.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("must be %s %s characters long", bound, fe.Param())
+		case reflect.Slice, reflect.Map, reflect.Array:
+			return fmt.Sprintf("must have %s %s items", bound, fe.Param())
+		default:
+			return fmt.Sprintf("must be %s %s", bound, fe.Param())
+		}
+	}
+	return "is invalid"
+}
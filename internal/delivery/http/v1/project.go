@@ -0,0 +1,182 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/yosakoo/task-traker/internal/service"
+)
+
+func (h *Handler) initProjectsRoutes(router chi.Router) {
+	router.Route("/projects", func(r chi.Router) {
+		r.Use(h.AuthMiddleware)
+		r.Use(h.IdempotencyMiddleware)
+
+		r.Post("/", h.createProject)
+		r.Get("/", h.getUserProjects)
+		r.Get("/{projectID}", h.getProjectByID)
+		r.Put("/{projectID}", h.updateProject)
+		r.Delete("/{projectID}", h.deleteProject)
+		r.Get("/{projectID}/tasks", h.getProjectTasks)
+	})
+}
+
+type projectInput struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+}
+
+func (h *Handler) createProject(w http.ResponseWriter, r *http.Request) {
+	var input projectInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := h.validate.Struct(input); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	projectID, err := h.services.Projects.CreateProject(r.Context(), userId, service.ProjectInput{
+		Name:        input.Name,
+		Description: input.Description,
+	})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(strconv.Itoa(projectID)))
+}
+
+func (h *Handler) getUserProjects(w http.ResponseWriter, r *http.Request) {
+	userId := r.Context().Value("user_id").(int)
+	projects, err := h.services.Projects.GetUserProjects(r.Context(), userId)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	jsonResponse, err := json.Marshal(projects)
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonResponse)
+}
+
+func (h *Handler) getProjectByID(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "projectID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid project ID")
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	project, err := h.services.Projects.GetProjectByID(r.Context(), userId, projectID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	jsonResponse, err := json.Marshal(project)
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonResponse)
+}
+
+func (h *Handler) updateProject(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "projectID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid project ID")
+		return
+	}
+
+	var input projectInput
+	err = json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := h.validate.Struct(input); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	err = h.services.Projects.UpdateProject(r.Context(), userId, projectID, service.ProjectInput{
+		Name:        input.Name,
+		Description: input.Description,
+	})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) deleteProject(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "projectID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid project ID")
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	err = h.services.Projects.DeleteProject(r.Context(), userId, projectID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) getProjectTasks(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(chi.URLParam(r, "projectID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid project ID")
+		return
+	}
+
+	input, err := parseTaskListQuery(r)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	list, err := h.services.Projects.GetProjectTasks(r.Context(), userId, projectID, input)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	jsonResponse, err := json.Marshal(getUserTasksResponse{
+		Groups:     list.Groups,
+		NextCursor: list.NextCursor,
+	})
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonResponse)
+}
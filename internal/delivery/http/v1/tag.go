@@ -0,0 +1,157 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/yosakoo/task-traker/internal/service"
+)
+
+func (h *Handler) initTagsRoutes(router chi.Router) {
+	router.Route("/tags", func(r chi.Router) {
+		r.Use(h.AuthMiddleware)
+		r.Use(h.IdempotencyMiddleware)
+
+		r.Post("/", h.createTag)
+		r.Get("/", h.getUserTags)
+		r.Patch("/{tagID}", h.updateTag)
+		r.Delete("/{tagID}", h.deleteTag)
+	})
+}
+
+type tagInput struct {
+	Name  string `json:"name" validate:"required,max=50"`
+	Color string `json:"color" validate:"omitempty,hexcolor,max=7"`
+}
+
+type tagUpdateInput struct {
+	Name  *string `json:"name" validate:"omitempty,min=1,max=50"`
+	Color *string `json:"color" validate:"omitempty,hexcolor,max=7"`
+}
+
+func (h *Handler) createTag(w http.ResponseWriter, r *http.Request) {
+	var input tagInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := h.validate.Struct(input); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	tagID, err := h.services.Tags.CreateTag(r.Context(), userId, service.TagInput{
+		Name:  input.Name,
+		Color: input.Color,
+	})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(strconv.Itoa(tagID)))
+}
+
+func (h *Handler) getUserTags(w http.ResponseWriter, r *http.Request) {
+	userId := r.Context().Value("user_id").(int)
+	tags, err := h.services.Tags.GetUserTags(r.Context(), userId)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	jsonResponse, err := json.Marshal(tags)
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonResponse)
+}
+
+func (h *Handler) updateTag(w http.ResponseWriter, r *http.Request) {
+	tagID, err := strconv.Atoi(chi.URLParam(r, "tagID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid tag ID")
+		return
+	}
+
+	var input tagUpdateInput
+	err = json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := h.validate.Struct(input); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	err = h.services.Tags.UpdateTag(r.Context(), userId, tagID, service.TagUpdateInput{
+		Name:  input.Name,
+		Color: input.Color,
+	})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) deleteTag(w http.ResponseWriter, r *http.Request) {
+	tagID, err := strconv.Atoi(chi.URLParam(r, "tagID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid tag ID")
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	err = h.services.Tags.DeleteTag(r.Context(), userId, tagID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) attachTag(w http.ResponseWriter, r *http.Request) {
+	h.changeTaskTag(w, r, h.services.Tags.AttachTag)
+}
+
+func (h *Handler) detachTag(w http.ResponseWriter, r *http.Request) {
+	h.changeTaskTag(w, r, h.services.Tags.DetachTag)
+}
+
+func (h *Handler) changeTaskTag(w http.ResponseWriter, r *http.Request, change func(ctx context.Context, userID, taskID, tagID int) error) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+	tagID, err := strconv.Atoi(chi.URLParam(r, "tagID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid tag ID")
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	err = change(r.Context(), userId, taskID, tagID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
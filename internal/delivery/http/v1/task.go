@@ -6,34 +6,75 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
-	"github.com/yosakoo/task-traker/internal/domain"
 	"github.com/yosakoo/task-traker/internal/service"
 )
 
 func (h *Handler) initTasksRoutes(router chi.Router) {
 	router.Route("/tasks", func(r chi.Router) {
 		r.Use(h.AuthMiddleware)
+		r.Use(h.IdempotencyMiddleware)
 
 		r.Post("/", h.createTask)
+		r.Post("/bulk", h.bulkUpdateTasks)
 		r.Get("/{taskID}", h.getTaskByID)
 		r.Get("/", h.getUserTasks)
 		r.Put("/{taskID}", h.updateTask)
+		r.Patch("/{taskID}", h.patchTask)
 		r.Delete("/{taskID}", h.deleteTask)
+		r.Put("/{taskID}/project", h.moveTask)
+		r.Put("/{taskID}/parent", h.setTaskParent)
+		r.Get("/{taskID}/subtasks", h.getSubtasks)
+		r.Get("/{taskID}/history", h.getTaskHistory)
+		r.Get("/{taskID}/checklist", h.getChecklist)
+		r.Post("/{taskID}/checklist", h.addChecklistItem)
+		r.Put("/{taskID}/checklist/{itemID}", h.updateChecklistItem)
+		r.Delete("/{taskID}/checklist/{itemID}", h.deleteChecklistItem)
+		r.Get("/{taskID}/comments", h.getComments)
+		r.Post("/{taskID}/comments", h.createComment)
+		r.Put("/{taskID}/comments/{commentID}", h.updateComment)
+		r.Delete("/{taskID}/comments/{commentID}", h.deleteComment)
+		r.Get("/{taskID}/attachments", h.getAttachments)
+		r.Post("/{taskID}/attachments", h.uploadAttachment)
+		r.Get("/{taskID}/attachments/{attachmentID}", h.downloadAttachment)
+		r.Delete("/{taskID}/attachments/{attachmentID}", h.deleteAttachment)
+		r.Post("/{taskID}/dependencies", h.addTaskDependency)
+		r.Delete("/{taskID}/dependencies/{blockerID}", h.removeTaskDependency)
+		r.Put("/{taskID}/tags/{tagID}", h.attachTag)
+		r.Delete("/{taskID}/tags/{tagID}", h.detachTag)
 	})
 }
 
 type taskInput struct {
-	Title string  `json:"title" validate:"required"`
-	Status string `json:"status"`
-	Text  string  `json:"text"`
+	ProjectID    *int       `json:"project_id"`
+	ParentID     *int       `json:"parent_id"`
+	Title        string     `json:"title" validate:"required"`
+	Status       string     `json:"status"`
+	Text         string     `json:"text"`
+	Priority     string     `json:"priority" validate:"omitempty,oneof=low normal high urgent"`
+	DueAt        *time.Time `json:"due_at"`
+	AutoComplete *bool      `json:"auto_complete"`
+	Recurrence   *string    `json:"recurrence"`
+}
+
+type moveTaskInput struct {
+	ProjectID *int `json:"project_id"`
+}
+
+type dependencyInput struct {
+	BlockedBy int `json:"blocked_by" validate:"required"`
+}
+
+type setParentInput struct {
+	ParentID *int `json:"parent_id"`
 }
 
 type getUserTasksResponse struct {
-	CompletedTasks []service.TaskOut `json:"completed"`
-	PendingTasks   []service.TaskOut `json:"pending"`
+	Groups     []service.TaskGroup `json:"groups"`
+	NextCursor string              `json:"next_cursor,omitempty"`
 }
 
 func (h *Handler) createTask(w http.ResponseWriter, r *http.Request) {
@@ -41,26 +82,29 @@ func (h *Handler) createTask(w http.ResponseWriter, r *http.Request) {
 
 	err := json.NewDecoder(r.Body).Decode(&input)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("invalid request body"))
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
 	if err := h.validate.Struct(input); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte(err.Error()))
+		writeValidationError(w, r, err)
 		return
 	}
 
 	userId := r.Context().Value("user_id").(int)
 	taskID, err := h.services.Tasks.CreateTask(r.Context(), userId, service.TaskInput{
-		Title: input.Title,
+		ProjectID:    input.ProjectID,
+		ParentID:     input.ParentID,
+		Title:        input.Title,
+		Text:         input.Text,
+		Priority:     input.Priority,
+		DueAt:        input.DueAt,
+		AutoComplete: input.AutoComplete,
+		Recurrence:   input.Recurrence,
 	})
 	
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		fmt.Println(err)
-		w.Write([]byte("could not create task"))
+		h.writeError(w, r, err)
 		return
 	}
 
@@ -71,28 +115,27 @@ func (h *Handler) createTask(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) getTaskByID(w http.ResponseWriter, r *http.Request) {
 	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("invalid task ID"))
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
 		return
 	}
 
-	task, err := h.services.Tasks.GetTaskByID(r.Context(), taskID)
+	userId := r.Context().Value("user_id").(int)
+	task, err := h.services.Tasks.GetTaskByID(r.Context(), userId, taskID)
 	if err != nil {
-		if errors.Is(err, domain.ErrTaskNotFound) {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte("task not found"))
-			return
-		}
+		h.writeError(w, r, err)
+		return
+	}
 
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("could not get task"))
+	etag := taskETag(task.Version)
+	w.Header().Set("ETag", etag)
+	if noneMatch(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
 	jsonResponse, err := json.Marshal(task)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("could not marshal response"))
+		writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
 		return
 	}
 
@@ -101,32 +144,101 @@ func (h *Handler) getTaskByID(w http.ResponseWriter, r *http.Request) {
 	w.Write(jsonResponse)
 }
 
+// parseTimeParam reads an RFC 3339 time or a date. Dates given as an upper
+// bound include the whole day, they are moved to its last microsecond, the
+// precision timestamps are stored with.
+func parseTimeParam(r *http.Request, name string, upper bool) (*time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.Parse(time.DateOnly, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s", name)
+		}
+		if upper {
+			t = t.AddDate(0, 0, 1).Add(-time.Microsecond)
+		}
+	}
+	t = t.UTC()
+	return &t, nil
+}
+
+func parseTaskListQuery(r *http.Request) (service.TaskListInput, error) {
+	query := r.URL.Query()
+	input := service.TaskListInput{
+		Status:   query.Get("status"),
+		Search:   query.Get("q"),
+		Tags:     query["tag"],
+		TagMatch: query.Get("tag_mode"),
+		SortBy:   query.Get("sort"),
+		Order:    query.Get("order"),
+		Cursor:   query.Get("cursor"),
+	}
+
+	var err error
+	if input.CreatedFrom, err = parseTimeParam(r, "created_from", false); err != nil {
+		return input, err
+	}
+	if input.CreatedTo, err = parseTimeParam(r, "created_to", true); err != nil {
+		return input, err
+	}
+	if input.CompletedFrom, err = parseTimeParam(r, "completed_from", false); err != nil {
+		return input, err
+	}
+	if input.CompletedTo, err = parseTimeParam(r, "completed_to", true); err != nil {
+		return input, err
+	}
+
+	if overdue := query.Get("overdue"); overdue != "" {
+		input.Overdue, err = strconv.ParseBool(overdue)
+		if err != nil {
+			return input, errors.New("invalid overdue")
+		}
+	}
+
+	if seriesID := query.Get("series_id"); seriesID != "" {
+		id, err := strconv.Atoi(seriesID)
+		if err != nil {
+			return input, errors.New("invalid series_id")
+		}
+		input.SeriesID = &id
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		input.Limit, err = strconv.Atoi(limit)
+		if err != nil || input.Limit <= 0 {
+			return input, errors.New("invalid limit")
+		}
+	}
+
+	return input, nil
+}
+
 func (h *Handler) getUserTasks(w http.ResponseWriter, r *http.Request) {
+	input, err := parseTaskListQuery(r)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	userId := r.Context().Value("user_id").(int)
-	completedTasks, pendingTasks, err := h.services.Tasks.GetUserTasks(r.Context(), userId)
+	list, err := h.services.Tasks.GetUserTasks(r.Context(), userId, input)
 	if err != nil {
-		fmt.Println(err)
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("could not get tasks"))
+		h.writeError(w, r, err)
 		return
 	}
 	
-	if completedTasks == nil {
-		completedTasks = []service.TaskOut{}
-	}
-	if pendingTasks == nil {
-		pendingTasks = []service.TaskOut{}
-	}
-
 	response := getUserTasksResponse{
-		CompletedTasks: completedTasks,
-		PendingTasks:   pendingTasks,
+		Groups:     list.Groups,
+		NextCursor: list.NextCursor,
 	}
 
 	jsonResponse, err := json.Marshal(response)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("could not marshal response"))
+		writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
 		return
 	}
 
@@ -138,49 +250,230 @@ func (h *Handler) getUserTasks(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) updateTask(w http.ResponseWriter, r *http.Request) {
 	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("invalid task ID"))
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
 		return
 	}
 
 	var input taskInput
 	err = json.NewDecoder(r.Body).Decode(&input)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("invalid request body"))
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
 		return
 	}
-	if input.Title == ""{
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("invalid title"))
+	if err := h.validate.Struct(input); err != nil {
+		writeValidationError(w, r, err)
 		return
 	}
-	err = h.services.Tasks.UpdateTask(r.Context(), taskID, service.TaskInput{
-		Title: input.Title,
-		Status: input.Status,
-		Text:  input.Text,
+	var force bool
+	if value := r.URL.Query().Get("force"); value != "" {
+		force, err = strconv.ParseBool(value)
+		if err != nil {
+			writeProblem(w, r, http.StatusBadRequest, "invalid force")
+			return
+		}
+	}
+	version, err := h.ifMatchVersion(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	userId := r.Context().Value("user_id").(int)
+	err = h.services.Tasks.UpdateTask(r.Context(), userId, taskID, service.TaskInput{
+		Title:        input.Title,
+		Status:       input.Status,
+		Text:         input.Text,
+		Priority:     input.Priority,
+		DueAt:        input.DueAt,
+		AutoComplete: input.AutoComplete,
+		Recurrence:   input.Recurrence,
+		Force:        force,
+		Version:      version,
 	})
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("could not update task"))
+		h.writeError(w, r, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	h.writeTaskETag(w, r, userId, taskID)
 }
 
 func (h *Handler) deleteTask(w http.ResponseWriter, r *http.Request) {
 	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("invalid task ID"))
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+
+	version, err := h.ifMatchVersion(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	err = h.services.Tasks.DeleteTask(r.Context(), userId, taskID, version)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) moveTask(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+
+	var input moveTaskInput
+	err = json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	version, err := h.ifMatchVersion(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	err = h.services.Tasks.MoveTask(r.Context(), userId, taskID, input.ProjectID, version)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeTaskETag(w, r, userId, taskID)
+}
+
+func (h *Handler) setTaskParent(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+
+	var input setParentInput
+	err = json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	version, err := h.ifMatchVersion(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	err = h.services.Tasks.SetParent(r.Context(), userId, taskID, input.ParentID, version)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeTaskETag(w, r, userId, taskID)
+}
+
+func (h *Handler) getSubtasks(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	subtasks, err := h.services.Tasks.GetSubtasks(r.Context(), userId, taskID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	jsonResponse, err := json.Marshal(subtasks)
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonResponse)
+}
+
+func (h *Handler) getTaskHistory(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
 		return
 	}
 
-	err = h.services.Tasks.DeleteTask(r.Context(), taskID)
+	userId := r.Context().Value("user_id").(int)
+	events, err := h.services.Tasks.GetTaskHistory(r.Context(), userId, taskID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	jsonResponse, err := json.Marshal(events)
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonResponse)
+}
+
+func (h *Handler) addTaskDependency(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+
+	var input dependencyInput
+	err = json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := h.validate.Struct(input); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	err = h.services.Tasks.AddDependency(r.Context(), userId, taskID, input.BlockedBy)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) removeTaskDependency(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+	blockerID, err := strconv.Atoi(chi.URLParam(r, "blockerID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid blocker ID")
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	err = h.services.Tasks.RemoveDependency(r.Context(), userId, taskID, blockerID)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("could not delete task"))
+		h.writeError(w, r, err)
 		return
 	}
 
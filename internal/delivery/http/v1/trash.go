@@ -0,0 +1,73 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handler) initTrashRoutes(router chi.Router) {
+	router.Route("/trash", func(r chi.Router) {
+		r.Use(h.AuthMiddleware)
+		r.Use(h.IdempotencyMiddleware)
+
+		r.Get("/", h.getTrash)
+		r.Post("/{taskID}/restore", h.restoreTask)
+		r.Delete("/{taskID}", h.purgeTask)
+	})
+}
+
+func (h *Handler) getTrash(w http.ResponseWriter, r *http.Request) {
+	userId := r.Context().Value("user_id").(int)
+	tasks, err := h.services.Trash.GetTrash(r.Context(), userId)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	jsonResponse, err := json.Marshal(tasks)
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonResponse)
+}
+
+func (h *Handler) restoreTask(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	err = h.services.Trash.RestoreTask(r.Context(), userId, taskID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) purgeTask(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	err = h.services.Trash.DeleteTask(r.Context(), userId, taskID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -3,7 +3,6 @@ package v1
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -15,13 +14,21 @@ import (
 
 func (h *Handler) initUsersRoutes(router chi.Router) {
 	router.Route("/users", func(r chi.Router) {
+		// no idempotency keys here: without a user they'd share one key
+		// space, and sign-up, sign-in and refresh answer with live tokens
+		// that mustn't be stored
 		r.Post("/sign-up", h.userSignUp)
 		r.Post("/sign-in", h.userSignIn)
 		r.Post("/auth/refresh", h.userRefresh)
+		r.Post("/password/forgot", h.forgotPassword)
+		r.Post("/password/reset", h.resetPassword)
+		r.Post("/verify", h.verifyEmail)
 
 		r.Group(func(r chi.Router) {
 			r.Use(h.AuthMiddleware)
+			r.Use(h.IdempotencyMiddleware)
 			r.Get("/", h.getCurrentUser)
+			r.Post("/verify/resend", h.resendVerification)
 		})
 	})
 }
@@ -43,6 +50,19 @@ type authUser struct {
 	Email string `json:"email"`
 }
 
+type forgotPasswordInput struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type resetPasswordInput struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=6"`
+}
+
+type verifyEmailInput struct {
+	Token string `json:"token" validate:"required"`
+}
+
 type refreshInput struct {
 	Token string `json:"token"`
 }
@@ -56,13 +76,11 @@ func (h *Handler) userSignUp(w http.ResponseWriter, r *http.Request) {
 	var input userSignUpInput
 	err := json.NewDecoder(r.Body).Decode(&input)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("invalid request body"))
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
 		return
 	}
 	if err := h.validate.Struct(input); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte(err.Error()))
+		writeValidationError(w, r, err)
 		return
 	}
 	ctx := r.Context()
@@ -72,14 +90,7 @@ func (h *Handler) userSignUp(w http.ResponseWriter, r *http.Request) {
 		Password: input.Password,
 	})
 	if err != nil {
-		if errors.Is(err, domain.ErrUserAlreadyExists) {
-			w.WriteHeader(http.StatusConflict)
-			w.Write([]byte("this email is already taken"))
-			return
-		}
-		w.WriteHeader(http.StatusInternalServerError)
-		fmt.Println(err)
-		w.Write([]byte("could not sign up user"))
+		h.writeError(w, r, err)
 		return
 	}
 	
@@ -89,8 +100,7 @@ func (h *Handler) userSignUp(w http.ResponseWriter, r *http.Request) {
 	}
 	jsonResponse, err := json.Marshal(response)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("could not marshal response"))
+		writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -102,13 +112,11 @@ func (h *Handler) userSignIn(w http.ResponseWriter, r *http.Request) {
     var input userSignInInput
     err := json.NewDecoder(r.Body).Decode(&input)
     if err != nil {
-        w.WriteHeader(http.StatusBadRequest)
-        w.Write([]byte("invalid request body"))
+        writeProblem(w, r, http.StatusBadRequest, "invalid request body")
         return
     }
 	if err := h.validate.Struct(input); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte(err.Error()))
+		writeValidationError(w, r, err)
 		return
 	}
 
@@ -118,15 +126,13 @@ func (h *Handler) userSignIn(w http.ResponseWriter, r *http.Request) {
         Password: input.Password,
     })
     if err != nil {
-		fmt.Println(err)
+		// an unknown email and a wrong password look the same to the client
 		if errors.Is(err, domain.ErrUserNotFound) {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte("user not found"))
+			writeProblem(w, r, http.StatusUnauthorized, "invalid email or password")
 			return
 		}
-        w.WriteHeader(http.StatusInternalServerError)
-        w.Write([]byte("could not sign in user"))
-        return
+		h.writeError(w, r, err)
+		return
     }
     response := tokenResponse{
         AccessToken:  res.AccessToken,
@@ -134,13 +140,12 @@ func (h *Handler) userSignIn(w http.ResponseWriter, r *http.Request) {
     }
     jsonResponse, err := json.Marshal(response)
     if err != nil {
-        w.WriteHeader(http.StatusInternalServerError)
-        w.Write([]byte("could not marshal response"))
+        writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
         return
     }
 
     w.Header().Set("Content-Type", "application/json")
-    w.WriteHeader(http.StatusCreated)
+    w.WriteHeader(http.StatusOK)
     w.Write(jsonResponse)
 }
 
@@ -148,25 +153,15 @@ func (h *Handler) userRefresh(w http.ResponseWriter, r *http.Request) {
 	var input refreshInput
 	err := json.NewDecoder(r.Body).Decode(&input)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("invalid request body"))
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
 	ctx := r.Context()
-	fmt.Println(input.Token)
 
 	res, err := h.services.Users.RefreshTokens(ctx, input.Token)
 	if err != nil {
-		if errors.Is(err, domain.ErrTokenExpired) {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte("token has expired"))
-			return
-		}
-
-		fmt.Println(err)
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("could not refresh"))
+		h.writeError(w, r, err)
 		return
 	}
 
@@ -176,12 +171,11 @@ func (h *Handler) userRefresh(w http.ResponseWriter, r *http.Request) {
 	}
 	jsonResponse, err := json.Marshal(response)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("could not marshal response"))
+		writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(http.StatusOK)
 	w.Write(jsonResponse)
 
 }
@@ -191,19 +185,12 @@ func (h *Handler) getCurrentUser(w http.ResponseWriter, r *http.Request) {
     userId := r.Context().Value("user_id").(int)
     user, err := h.services.Users.GetUserByID(r.Context(), userId)
     if err != nil {
-		if errors.Is(err, domain.ErrUserNotFound) {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte("user not found"))
-			return
-		}
-        w.WriteHeader(http.StatusInternalServerError)
-        w.Write([]byte("could not get user"))
+        h.writeError(w, r, err)
         return
     }
     jsonResponse, err := json.Marshal(user)
     if err != nil {
-        w.WriteHeader(http.StatusInternalServerError)
-        w.Write([]byte("could not marshal response"))
+        writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
         return
     }
 
@@ -211,3 +198,75 @@ func (h *Handler) getCurrentUser(w http.ResponseWriter, r *http.Request) {
     w.WriteHeader(http.StatusOK)
     w.Write(jsonResponse)
 }
+
+// forgotPassword answers the same whether or not the email belongs to a
+// user, so it can't be used to find out who has an account.
+func (h *Handler) forgotPassword(w http.ResponseWriter, r *http.Request) {
+	var input forgotPasswordInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := h.validate.Struct(input); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	if err := h.services.Users.ForgotPassword(r.Context(), input.Email); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *Handler) resetPassword(w http.ResponseWriter, r *http.Request) {
+	var input resetPasswordInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := h.validate.Struct(input); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	if err := h.services.Users.ResetPassword(r.Context(), input.Token, input.Password); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) verifyEmail(w http.ResponseWriter, r *http.Request) {
+	var input verifyEmailInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := h.validate.Struct(input); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	if err := h.services.Users.VerifyEmail(r.Context(), input.Token); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) resendVerification(w http.ResponseWriter, r *http.Request) {
+	userId := r.Context().Value("user_id").(int)
+	if err := h.services.Users.ResendVerification(r.Context(), userId); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
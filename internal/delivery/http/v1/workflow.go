@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/yosakoo/task-traker/internal/service"
+)
+
+func (h *Handler) initWorkflowRoutes(router chi.Router) {
+	router.Route("/workflow", func(r chi.Router) {
+		r.Use(h.AuthMiddleware)
+		r.Use(h.IdempotencyMiddleware)
+
+		r.Get("/", h.getWorkflow)
+		r.Put("/", h.setWorkflow)
+	})
+}
+
+type workflowInput struct {
+	Statuses    []service.WorkflowStatus     `json:"statuses" validate:"required,min=1"`
+	Transitions []service.WorkflowTransition `json:"transitions"`
+}
+
+func (h *Handler) getWorkflow(w http.ResponseWriter, r *http.Request) {
+	userId := r.Context().Value("user_id").(int)
+	wf, err := h.services.Workflows.GetWorkflow(r.Context(), userId)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	jsonResponse, err := json.Marshal(wf)
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "could not marshal response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonResponse)
+}
+
+func (h *Handler) setWorkflow(w http.ResponseWriter, r *http.Request) {
+	var input workflowInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := h.validate.Struct(input); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	userId := r.Context().Value("user_id").(int)
+	err = h.services.Workflows.SetWorkflow(r.Context(), userId, service.WorkflowInput{
+		Statuses:    input.Statuses,
+		Transitions: input.Transitions,
+	})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -3,8 +3,46 @@ package domain
 import "errors"
 
 var (
-	ErrUserNotFound            = errors.New("user doesn't exists")
-	ErrUserAlreadyExists       = errors.New("user with such email already exists")
-	ErrTokenExpired            = errors.New("token has expired")
-	ErrTaskNotFound            = errors.New("task doesn't exists")
-)
\ No newline at end of file
+	ErrUserNotFound          = errors.New("user doesn't exists")
+	ErrUserAlreadyExists     = errors.New("user with such email already exists")
+	ErrTokenExpired          = errors.New("token has expired")
+	ErrInvalidResetToken     = errors.New("password reset token is invalid or has expired")
+	ErrInvalidVerifyToken    = errors.New("email verification token is invalid or has expired")
+	ErrEmailAlreadyVerified  = errors.New("email is already verified")
+	ErrVerificationThrottled = errors.New("verification email was sent recently, try again later")
+	ErrEmailNotVerified      = errors.New("email has to be verified first")
+	ErrTaskNotFound          = errors.New("task doesn't exists")
+	ErrTaskVersionMismatch   = errors.New("task was changed since it was read")
+	ErrTaskForbidden         = errors.New("task belongs to another user")
+	ErrInvalidTaskFilter     = errors.New("invalid task filter")
+	ErrInvalidCursor         = errors.New("invalid pagination cursor")
+	ErrInvalidPriority       = errors.New("invalid task priority")
+	ErrSubtaskCycle          = errors.New("task can't be a subtask of itself")
+	ErrSubtaskTooDeep        = errors.New("subtasks are nested too deep")
+	ErrChecklistItemNotFound = errors.New("checklist item doesn't exists")
+	ErrCommentNotFound       = errors.New("comment doesn't exists")
+	ErrCommentForbidden      = errors.New("comment belongs to another user")
+	ErrAttachmentNotFound    = errors.New("attachment doesn't exists")
+	ErrAttachmentTooLarge    = errors.New("attachment is too large")
+	ErrAttachmentType        = errors.New("attachment type is not allowed")
+	ErrDependencyNotFound    = errors.New("dependency doesn't exists")
+	ErrDependencyCycle       = errors.New("dependency would create a cycle")
+	ErrTaskBlocked           = errors.New("task is blocked by unfinished tasks")
+	ErrInvalidRecurrence     = errors.New("invalid recurrence rule")
+	ErrOccurrenceExists      = errors.New("occurrence of a recurring task already exists")
+	ErrBatchAborted          = errors.New("not applied, another item of the batch failed")
+	ErrInvalidBatch          = errors.New("invalid batch operation")
+	ErrProjectNotFound       = errors.New("project doesn't exists")
+	ErrProjectForbidden      = errors.New("project belongs to another user")
+	ErrTagNotFound           = errors.New("tag doesn't exists")
+	ErrTagForbidden          = errors.New("tag belongs to another user")
+	ErrTagAlreadyExists      = errors.New("tag with such name already exists")
+	ErrInvalidTagName        = errors.New("invalid tag name")
+	ErrIdempotencyKeyReused  = errors.New("idempotency key was already used for a different request")
+	ErrIdempotencyKeyInUse   = errors.New("request with this idempotency key is still in progress")
+	ErrWorkflowNotFound      = errors.New("workflow doesn't exists")
+	ErrInvalidWorkflow       = errors.New("invalid workflow")
+	ErrWorkflowStatusInUse   = errors.New("workflow status is used by existing tasks")
+	ErrInvalidStatus         = errors.New("status is not part of the workflow")
+	ErrStatusTransition      = errors.New("status transition is not allowed")
+)
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+type Attachment struct {
+	ID          int
+	TaskID      int
+	UserID      int
+	Filename    string
+	ContentType string
+	Size        int64
+	// StorageKey points to the file contents in the blob store.
+	StorageKey string
+	CreatedAt  time.Time
+}
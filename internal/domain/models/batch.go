@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+type BatchAction string
+
+const (
+	BatchSetStatus   BatchAction = "update_status"
+	BatchSetTags     BatchAction = "set_tags"
+	BatchMoveProject BatchAction = "move_project"
+	BatchDelete      BatchAction = "delete"
+)
+
+// BatchItem is one change to one task within a batch. Which of the fields
+// are used depends on the action.
+type BatchItem struct {
+	Action BatchAction
+	TaskID int
+	// Status and CompletedAt are set for BatchSetStatus.
+	Status      string
+	CompletedAt *time.Time
+	// TagIDs replace all tags of the task for BatchSetTags.
+	TagIDs    []int
+	ProjectID *int
+}
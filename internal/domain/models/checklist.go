@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+type ChecklistItem struct {
+	ID        int
+	TaskID    int
+	Text      string
+	Done      bool
+	Position  int
+	CreatedAt time.Time
+}
+
+// Progress counts finished parts of a task, subtasks or checklist items.
+type Progress struct {
+	Done  int
+	Total int
+}
+
+func (p Progress) Add(other Progress) Progress {
+	return Progress{Done: p.Done + other.Done, Total: p.Total + other.Total}
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+type Comment struct {
+	ID        int
+	TaskID    int
+	AuthorID  int
+	Text      string
+	CreatedAt time.Time
+	// EditedAt is nil until the comment is changed.
+	EditedAt *time.Time
+}
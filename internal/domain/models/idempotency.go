@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// IdempotencyRecord is a request made with an Idempotency-Key and, once it
+// has been handled, the response to it. StatusCode is 0 while the request is
+// still in progress.
+type IdempotencyRecord struct {
+	UserID      int
+	Key         string
+	Fingerprint string
+	StatusCode  int
+	Headers     map[string][]string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+)
+
+// Nullable is a field of a partial update. Set tells whether the field is
+// changed at all, a nil Value then clears it.
+type Nullable[T any] struct {
+	Set   bool
+	Value *T
+}
+
+// Update returns a Nullable that sets the field to v.
+func Update[T any](v T) Nullable[T] {
+	return Nullable[T]{Set: true, Value: &v}
+}
+
+// TaskPatch lists the columns a partial update writes, fields that aren't
+// set are left as they are.
+type TaskPatch struct {
+	Title        Nullable[string]
+	Status       Nullable[string]
+	Text         Nullable[string]
+	Priority     Nullable[Priority]
+	DueAt        Nullable[time.Time]
+	CompletedAt  Nullable[time.Time]
+	AutoComplete Nullable[bool]
+	Recurrence   Nullable[string]
+	ProjectID    Nullable[int]
+	UpdatedAt    time.Time
+	// Version is the version the task is expected at, 0 patches any version.
+	Version int
+}
+
+// Apply returns the task as it is after the patch.
+func (p TaskPatch) Apply(task Task) Task {
+	if p.Title.Set {
+		task.Title = *p.Title.Value
+	}
+	if p.Status.Set {
+		task.Status = *p.Status.Value
+	}
+	if p.Text.Set {
+		task.Text = p.Text.Value
+	}
+	if p.Priority.Set {
+		task.Priority = *p.Priority.Value
+	}
+	if p.DueAt.Set {
+		task.DueAt = p.DueAt.Value
+	}
+	if p.CompletedAt.Set {
+		task.CompletedAt = p.CompletedAt.Value
+	}
+	if p.AutoComplete.Set {
+		task.AutoComplete = *p.AutoComplete.Value
+	}
+	if p.Recurrence.Set {
+		task.Recurrence = p.Recurrence.Value
+	}
+	if p.ProjectID.Set {
+		task.ProjectID = p.ProjectID.Value
+	}
+	task.UpdatedAt = p.UpdatedAt
+	return task
+}
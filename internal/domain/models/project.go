@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+type Project struct {
+	ID          int
+	UserID      int
+	Name        string
+	Description *string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,17 @@
+package models
+
+type Tag struct {
+	ID     int
+	UserID int
+	Name   string
+	Color  string
+}
+
+type TagMatch string
+
+const (
+	// TagMatchAll keeps tasks that have every requested tag.
+	TagMatchAll TagMatch = "all"
+	// TagMatchAny keeps tasks that have at least one requested tag.
+	TagMatchAny TagMatch = "any"
+)
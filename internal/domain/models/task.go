@@ -1,15 +1,103 @@
 package models
 
-import(
+import (
 	"time"
 )
 
-
 type Task struct {
-    ID     int
-    Status string
-    UserID int
-    Title  string
-    Text   *string
-    Time   *time.Time
-}
\ No newline at end of file
+	ID          int
+	Status      string
+	UserID      int
+	ProjectID   *int
+	ParentID    *int
+	Title       string
+	Text        *string
+	Priority    Priority
+	DueAt       *time.Time
+	CompletedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	// AutoComplete completes the task once all its subtasks are done.
+	AutoComplete bool
+	// Recurrence is an RRULE, completing the task creates its next occurrence.
+	Recurrence *string
+	// SeriesID is the ID of the first task of a recurring series.
+	SeriesID   *int
+	Occurrence int
+	// DeletedAt is set while the task is in the trash.
+	DeletedAt *time.Time
+	// Version goes up with every change of the task.
+	Version int
+}
+
+// Priority is stored as a number so that tasks sort by it naturally.
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+	PriorityUrgent
+)
+
+var priorityNames = []string{"low", "normal", "high", "urgent"}
+
+func (p Priority) String() string {
+	if p < PriorityLow || p > PriorityUrgent {
+		return "unknown"
+	}
+	return priorityNames[p]
+}
+
+func ParsePriority(s string) (Priority, bool) {
+	for i, name := range priorityNames {
+		if name == s {
+			return Priority(i), true
+		}
+	}
+	return 0, false
+}
+
+func (t Task) IsOverdue(now time.Time) bool {
+	return t.DueAt != nil && t.CompletedAt == nil && t.DueAt.Before(now)
+}
+
+type TaskSortField string
+
+const (
+	TaskSortID        TaskSortField = "id"
+	TaskSortCreatedAt TaskSortField = "created_at"
+	TaskSortUpdatedAt TaskSortField = "updated_at"
+	TaskSortDueAt     TaskSortField = "due_at"
+	TaskSortPriority  TaskSortField = "priority"
+	TaskSortTitle     TaskSortField = "title"
+)
+
+// TaskCursor points right after the last task of a page: the value of the
+// sort column of that task and its ID as a tiebreaker.
+type TaskCursor struct {
+	Value string
+	ID    int
+}
+
+type TaskFilter struct {
+	ProjectID     *int
+	SeriesID      *int
+	Status        string
+	Search        string
+	Tags          []string
+	TagMatch      TagMatch
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	CompletedFrom *time.Time
+	CompletedTo   *time.Time
+	// ProjectIDs keeps tasks of any of the projects, Limit then applies to
+	// each project on its own and tasks come grouped by project.
+	ProjectIDs []int
+	// OverdueAt keeps only tasks that are still open and were due before it.
+	OverdueAt *time.Time
+	SortBy    TaskSortField
+	Desc      bool
+	Limit     int
+	After     *TaskCursor
+}
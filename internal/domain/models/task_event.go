@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+)
+
+type TaskAction string
+
+const (
+	TaskCreated  TaskAction = "created"
+	TaskUpdated  TaskAction = "updated"
+	TaskDeleted  TaskAction = "deleted"
+	TaskRestored TaskAction = "restored"
+)
+
+// TaskEvent is one entry of a task's history. Updates have an event per
+// changed field, values are kept as text and are nil when the field is empty.
+type TaskEvent struct {
+	ID        int64
+	TaskID    int
+	UserID    int
+	Action    TaskAction
+	Field     *string
+	OldValue  *string
+	NewValue  *string
+	CreatedAt time.Time
+}
+
+// TaskEventCursor is a position in the events of a user. Events are read in
+// the order of the transaction that wrote them, then by ID.
+type TaskEventCursor struct {
+	XID uint64
+	ID  int64
+}
@@ -1,8 +1,12 @@
 package models
 
+import "time"
+
 type User struct {
 	ID       int    `json:"id"`
 	Name string 	`json:"username"`
 	Email    string `json:"email"`
 	Password []byte `json:"password"`
+	CreatedAt  time.Time  `json:"created_at"`
+	VerifiedAt *time.Time `json:"verified_at"`
 }
@@ -0,0 +1,85 @@
+package models
+
+type StatusCategory string
+
+const (
+	CategoryTodo       StatusCategory = "todo"
+	CategoryInProgress StatusCategory = "in_progress"
+	CategoryDone       StatusCategory = "done"
+)
+
+func (c StatusCategory) Valid() bool {
+	return c == CategoryTodo || c == CategoryInProgress || c == CategoryDone
+}
+
+type WorkflowStatus struct {
+	Name     string
+	Category StatusCategory
+}
+
+type WorkflowTransition struct {
+	From string
+	To   string
+}
+
+// Workflow lists the statuses a user's tasks can have, in display order,
+// and which status changes are allowed.
+type Workflow struct {
+	Statuses    []WorkflowStatus
+	Transitions []WorkflowTransition
+}
+
+// DefaultWorkflow is used for users who haven't defined their own.
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		Statuses: []WorkflowStatus{
+			{Name: "pending", Category: CategoryTodo},
+			{Name: "in_progress", Category: CategoryInProgress},
+			{Name: "completed", Category: CategoryDone},
+		},
+		Transitions: []WorkflowTransition{
+			{From: "pending", To: "in_progress"},
+			{From: "pending", To: "completed"},
+			{From: "in_progress", To: "pending"},
+			{From: "in_progress", To: "completed"},
+			{From: "completed", To: "pending"},
+			{From: "completed", To: "in_progress"},
+		},
+	}
+}
+
+func (w Workflow) Status(name string) (WorkflowStatus, bool) {
+	for _, s := range w.Statuses {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return WorkflowStatus{}, false
+}
+
+// InitialStatus is the first todo status, new tasks start in it.
+func (w Workflow) InitialStatus() string {
+	for _, s := range w.Statuses {
+		if s.Category == CategoryTodo {
+			return s.Name
+		}
+	}
+	return ""
+}
+
+// CanTransition reports whether a task may move from one status to another.
+// Tasks stuck in a status the workflow no longer knows may move anywhere.
+func (w Workflow) CanTransition(from, to string) bool {
+	if from == to {
+		return true
+	}
+	if _, ok := w.Status(from); !ok {
+		return true
+	}
+	for _, t := range w.Transitions {
+		if t.From == from && t.To == to {
+			return true
+		}
+	}
+	return false
+}
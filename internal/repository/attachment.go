@@ -0,0 +1,88 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yosakoo/task-traker/internal/domain"
+	"github.com/yosakoo/task-traker/internal/domain/models"
+	"github.com/yosakoo/task-traker/pkg/postgres"
+)
+
+type AttachmentRepo struct {
+	s *postgres.Storage
+}
+
+func NewAttachmentRepo(pg *postgres.Storage) *AttachmentRepo {
+	return &AttachmentRepo{s: pg}
+}
+
+const attachmentColumns = "id, task_id, user_id, filename, content_type, size, storage_key, created_at"
+
+func scanAttachment(row scanner, a *models.Attachment) error {
+	return row.Scan(&a.ID, &a.TaskID, &a.UserID, &a.Filename, &a.ContentType, &a.Size, &a.StorageKey, &a.CreatedAt)
+}
+
+func (r *AttachmentRepo) GetAttachments(ctx context.Context, taskID int) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	query := "SELECT " + attachmentColumns + " FROM task_attachments WHERE task_id = $1 ORDER BY id"
+	rows, err := r.s.Pool.Query(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a models.Attachment
+		if err := scanAttachment(rows, &a); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (r *AttachmentRepo) GetAttachmentByID(ctx context.Context, taskID, attachmentID int) (*models.Attachment, error) {
+	var a models.Attachment
+	query := "SELECT " + attachmentColumns + " FROM task_attachments WHERE id = $1 AND task_id = $2"
+	err := scanAttachment(r.s.Pool.QueryRow(ctx, query, attachmentID, taskID), &a)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrAttachmentNotFound
+		}
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+func (r *AttachmentRepo) CreateAttachment(ctx context.Context, a models.Attachment) (int, error) {
+	var attachmentID int
+	query := `INSERT INTO task_attachments (task_id, user_id, filename, content_type, size, storage_key, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	err := r.s.Pool.QueryRow(ctx, query, a.TaskID, a.UserID, a.Filename, a.ContentType, a.Size, a.StorageKey, a.CreatedAt).
+		Scan(&attachmentID)
+	if err != nil {
+		return 0, err
+	}
+
+	return attachmentID, nil
+}
+
+func (r *AttachmentRepo) DeleteAttachment(ctx context.Context, taskID, attachmentID int) error {
+	tag, err := r.s.Pool.Exec(ctx, "DELETE FROM task_attachments WHERE id = $1 AND task_id = $2", attachmentID, taskID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrAttachmentNotFound
+	}
+
+	return nil
+}
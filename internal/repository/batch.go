@@ -0,0 +1,106 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yosakoo/task-traker/internal/domain"
+	"github.com/yosakoo/task-traker/internal/domain/models"
+)
+
+// ApplyBatch runs all items in one transaction and returns an error for
+// each item, nil when it was applied. Every item runs in its own savepoint:
+// when atomic is false a failed item is rolled back alone and the rest are
+// committed. Otherwise the first failure rolls back the whole batch and all
+// the other items get domain.ErrBatchAborted.
+func (r *TaskRepo) ApplyBatch(ctx context.Context, userID int, items []models.BatchItem, atomic bool) ([]error, error) {
+	txOptions := pgx.TxOptions{}
+
+	tx, err := r.s.Pool.BeginTx(ctx, txOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	errs := make([]error, len(items))
+	for i, item := range items {
+		savepoint, err := tx.Begin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := applyBatchItem(ctx, savepoint, userID, item); err != nil {
+			if rbErr := savepoint.Rollback(ctx); rbErr != nil {
+				return nil, rbErr
+			}
+			if atomic {
+				for j := range errs {
+					errs[j] = domain.ErrBatchAborted
+				}
+				errs[i] = err
+				return errs, nil
+			}
+			errs[i] = err
+			continue
+		}
+		if err := savepoint.Commit(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, errors.New("error committing database transaction")
+	}
+
+	return errs, nil
+}
+
+func applyBatchItem(ctx context.Context, tx pgx.Tx, userID int, item models.BatchItem) error {
+	switch item.Action {
+	case models.BatchSetStatus:
+		return setTaskStatus(ctx, tx, userID, item.TaskID, item.Status, item.CompletedAt)
+	case models.BatchSetTags:
+		return setTaskTags(ctx, tx, userID, item.TaskID, item.TagIDs)
+	case models.BatchMoveProject:
+		return relinkTask(ctx, tx, userID, item.TaskID, 0, func(task *models.Task) { task.ProjectID = item.ProjectID })
+	case models.BatchDelete:
+		return trashTask(ctx, tx, userID, item.TaskID)
+	}
+	return errors.New("unknown batch action")
+}
+
+func setTaskStatus(ctx context.Context, tx pgx.Tx, userID, taskID int, status string, completedAt *time.Time) error {
+	old, err := lockTask(ctx, tx, userID, taskID)
+	if err != nil {
+		return err
+	}
+	updated := *old
+	updated.Status = status
+	updated.CompletedAt = completedAt
+	updated.UpdatedAt = time.Now().UTC()
+
+	query := "UPDATE tasks SET status = $1, completed_at = $2, updated_at = $3, version = version + 1 WHERE id = $4 AND user_id = $5"
+	_, err = tx.Exec(ctx, query, updated.Status, updated.CompletedAt, updated.UpdatedAt, taskID, userID)
+	if err != nil {
+		return err
+	}
+	return insertTaskEvents(ctx, tx, taskChanges(userID, *old, updated, updated.UpdatedAt))
+}
+
+// setTaskTags replaces the tags of a task, tags of other users are ignored.
+func setTaskTags(ctx context.Context, tx pgx.Tx, userID, taskID int, tagIDs []int) error {
+	if _, err := lockTask(ctx, tx, userID, taskID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM task_tags WHERE task_id = $1", taskID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "UPDATE tasks SET version = version + 1 WHERE id = $1", taskID); err != nil {
+		return err
+	}
+	query := `INSERT INTO task_tags (task_id, tag_id)
+		SELECT $1, id FROM tags WHERE id = ANY($2) AND user_id = $3 ON CONFLICT DO NOTHING`
+	_, err := tx.Exec(ctx, query, taskID, tagIDs, userID)
+	return err
+}
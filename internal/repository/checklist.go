@@ -0,0 +1,131 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yosakoo/task-traker/internal/domain"
+	"github.com/yosakoo/task-traker/internal/domain/models"
+	"github.com/yosakoo/task-traker/pkg/postgres"
+)
+
+type ChecklistRepo struct {
+	s *postgres.Storage
+}
+
+func NewChecklistRepo(pg *postgres.Storage) *ChecklistRepo {
+	return &ChecklistRepo{s: pg}
+}
+
+const checklistItemColumns = "id, task_id, text, done, position, created_at"
+
+func scanChecklistItem(row scanner, item *models.ChecklistItem) error {
+	return row.Scan(&item.ID, &item.TaskID, &item.Text, &item.Done, &item.Position, &item.CreatedAt)
+}
+
+func (r *ChecklistRepo) GetItems(ctx context.Context, taskID int) ([]models.ChecklistItem, error) {
+	var items []models.ChecklistItem
+	query := "SELECT " + checklistItemColumns + " FROM checklist_items WHERE task_id = $1 ORDER BY position, id"
+	rows, err := r.s.Pool.Query(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.ChecklistItem
+		if err := scanChecklistItem(rows, &item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *ChecklistRepo) GetItemByID(ctx context.Context, taskID, itemID int) (*models.ChecklistItem, error) {
+	var item models.ChecklistItem
+	query := "SELECT " + checklistItemColumns + " FROM checklist_items WHERE id = $1 AND task_id = $2"
+	err := scanChecklistItem(r.s.Pool.QueryRow(ctx, query, itemID, taskID), &item)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrChecklistItemNotFound
+		}
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (r *ChecklistRepo) AddItem(ctx context.Context, taskID int, item models.ChecklistItem) (int, error) {
+	var itemID int
+	query := `INSERT INTO checklist_items (task_id, text, done, position, created_at)
+		VALUES ($1, $2, $3, (SELECT COALESCE(MAX(position) + 1, 0) FROM checklist_items WHERE task_id = $1), $4)
+		RETURNING id`
+	err := r.s.Pool.QueryRow(ctx, query, taskID, item.Text, item.Done, item.CreatedAt).Scan(&itemID)
+	if err != nil {
+		return 0, err
+	}
+
+	return itemID, nil
+}
+
+func (r *ChecklistRepo) UpdateItem(ctx context.Context, taskID, itemID int, item models.ChecklistItem) error {
+	query := "UPDATE checklist_items SET text = $1, done = $2, position = $3 WHERE id = $4 AND task_id = $5"
+	tag, err := r.s.Pool.Exec(ctx, query, item.Text, item.Done, item.Position, itemID, taskID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrChecklistItemNotFound
+	}
+
+	return nil
+}
+
+func (r *ChecklistRepo) DeleteItem(ctx context.Context, taskID, itemID int) error {
+	tag, err := r.s.Pool.Exec(ctx, "DELETE FROM checklist_items WHERE id = $1 AND task_id = $2", itemID, taskID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrChecklistItemNotFound
+	}
+
+	return nil
+}
+
+func (r *ChecklistRepo) GetProgress(ctx context.Context, taskIDs []int) (map[int]models.Progress, error) {
+	progress := make(map[int]models.Progress, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return progress, nil
+	}
+
+	query := `SELECT task_id, COUNT(*) FILTER (WHERE done), COUNT(*) FROM checklist_items
+		WHERE task_id = ANY($1) GROUP BY task_id`
+	rows, err := r.s.Pool.Query(ctx, query, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID int
+		var p models.Progress
+		if err := rows.Scan(&taskID, &p.Done, &p.Total); err != nil {
+			return nil, err
+		}
+		progress[taskID] = p
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return progress, nil
+}
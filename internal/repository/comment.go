@@ -0,0 +1,100 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yosakoo/task-traker/internal/domain"
+	"github.com/yosakoo/task-traker/internal/domain/models"
+	"github.com/yosakoo/task-traker/pkg/postgres"
+)
+
+type CommentRepo struct {
+	s *postgres.Storage
+}
+
+func NewCommentRepo(pg *postgres.Storage) *CommentRepo {
+	return &CommentRepo{s: pg}
+}
+
+const commentColumns = "id, task_id, author_id, text, created_at, edited_at"
+
+func scanComment(row scanner, comment *models.Comment) error {
+	return row.Scan(&comment.ID, &comment.TaskID, &comment.AuthorID, &comment.Text, &comment.CreatedAt, &comment.EditedAt)
+}
+
+// GetComments returns comments of a task oldest first, starting after the comment afterID.
+func (r *CommentRepo) GetComments(ctx context.Context, taskID, afterID, limit int) ([]models.Comment, error) {
+	var comments []models.Comment
+	query := "SELECT " + commentColumns + " FROM task_comments WHERE task_id = $1 AND id > $2 ORDER BY id LIMIT $3"
+	rows, err := r.s.Pool.Query(ctx, query, taskID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var comment models.Comment
+		if err := scanComment(rows, &comment); err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (r *CommentRepo) GetCommentByID(ctx context.Context, taskID, commentID int) (*models.Comment, error) {
+	var comment models.Comment
+	query := "SELECT " + commentColumns + " FROM task_comments WHERE id = $1 AND task_id = $2"
+	err := scanComment(r.s.Pool.QueryRow(ctx, query, commentID, taskID), &comment)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrCommentNotFound
+		}
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+func (r *CommentRepo) CreateComment(ctx context.Context, comment models.Comment) (int, error) {
+	var commentID int
+	query := "INSERT INTO task_comments (task_id, author_id, text, created_at) VALUES ($1, $2, $3, $4) RETURNING id"
+	err := r.s.Pool.QueryRow(ctx, query, comment.TaskID, comment.AuthorID, comment.Text, comment.CreatedAt).Scan(&commentID)
+	if err != nil {
+		return 0, err
+	}
+
+	return commentID, nil
+}
+
+func (r *CommentRepo) UpdateComment(ctx context.Context, taskID, commentID int, comment models.Comment) error {
+	query := "UPDATE task_comments SET text = $1, edited_at = $2 WHERE id = $3 AND task_id = $4"
+	tag, err := r.s.Pool.Exec(ctx, query, comment.Text, comment.EditedAt, commentID, taskID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrCommentNotFound
+	}
+
+	return nil
+}
+
+func (r *CommentRepo) DeleteComment(ctx context.Context, taskID, commentID int) error {
+	tag, err := r.s.Pool.Exec(ctx, "DELETE FROM task_comments WHERE id = $1 AND task_id = $2", commentID, taskID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrCommentNotFound
+	}
+
+	return nil
+}
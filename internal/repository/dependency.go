@@ -0,0 +1,101 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/yosakoo/task-traker/internal/domain"
+	"github.com/yosakoo/task-traker/pkg/postgres"
+)
+
+type DependencyRepo struct {
+	s *postgres.Storage
+}
+
+func NewDependencyRepo(pg *postgres.Storage) *DependencyRepo {
+	return &DependencyRepo{s: pg}
+}
+
+func (r *DependencyRepo) AddDependency(ctx context.Context, taskID, blockedByID int) error {
+	query := "INSERT INTO task_dependencies (task_id, blocked_by_id) VALUES ($1, $2) ON CONFLICT DO NOTHING"
+	_, err := r.s.Pool.Exec(ctx, query, taskID, blockedByID)
+	return err
+}
+
+func (r *DependencyRepo) RemoveDependency(ctx context.Context, taskID, blockedByID int) error {
+	query := "DELETE FROM task_dependencies WHERE task_id = $1 AND blocked_by_id = $2"
+	tag, err := r.s.Pool.Exec(ctx, query, taskID, blockedByID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDependencyNotFound
+	}
+	return nil
+}
+
+// GetBlockers returns, for each of the given tasks, the tasks it is blocked by.
+func (r *DependencyRepo) GetBlockers(ctx context.Context, taskIDs []int) (map[int][]int, error) {
+	query := `SELECT d.task_id, d.blocked_by_id FROM task_dependencies d JOIN tasks t ON t.id = d.blocked_by_id
+		WHERE d.task_id = ANY($1) AND t.deleted_at IS NULL ORDER BY d.blocked_by_id`
+	return r.collect(ctx, query, taskIDs)
+}
+
+// GetBlocking returns, for each of the given tasks, the tasks waiting for it.
+func (r *DependencyRepo) GetBlocking(ctx context.Context, taskIDs []int) (map[int][]int, error) {
+	query := `SELECT d.blocked_by_id, d.task_id FROM task_dependencies d JOIN tasks t ON t.id = d.task_id
+		WHERE d.blocked_by_id = ANY($1) AND t.deleted_at IS NULL ORDER BY d.task_id`
+	return r.collect(ctx, query, taskIDs)
+}
+
+// GetOpenBlockers returns the blockers of a task that aren't completed yet.
+func (r *DependencyRepo) GetOpenBlockers(ctx context.Context, taskID int) ([]int, error) {
+	query := `SELECT d.blocked_by_id FROM task_dependencies d JOIN tasks t ON t.id = d.blocked_by_id
+		WHERE d.task_id = $1 AND t.completed_at IS NULL AND t.deleted_at IS NULL ORDER BY d.blocked_by_id`
+	rows, err := r.s.Pool.Query(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *DependencyRepo) collect(ctx context.Context, query string, taskIDs []int) (map[int][]int, error) {
+	result := make(map[int][]int, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.s.Pool.Query(ctx, query, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, value int
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		result[key] = append(result[key], value)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yosakoo/task-traker/internal/domain"
+	"github.com/yosakoo/task-traker/internal/domain/models"
+	"github.com/yosakoo/task-traker/pkg/postgres"
+)
+
+type IdempotencyRepo struct {
+	s *postgres.Storage
+}
+
+func NewIdempotencyRepo(pg *postgres.Storage) *IdempotencyRepo {
+	return &IdempotencyRepo{s: pg}
+}
+
+// ClaimKey stores record unless a record for the same key that hasn't expired
+// yet exists. That record is returned then, nil means the key was claimed.
+func (r *IdempotencyRepo) ClaimKey(ctx context.Context, record models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	query := `INSERT INTO idempotency_keys (user_id, key, fingerprint, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint, status_code = NULL, headers = NULL, body = NULL,
+			created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at`
+	tag, err := r.s.Pool.Exec(ctx, query, record.UserID, record.Key, record.Fingerprint, record.CreatedAt, record.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 1 {
+		return nil, nil
+	}
+
+	var existing models.IdempotencyRecord
+	var statusCode *int
+	query = `SELECT user_id, key, fingerprint, status_code, headers, body, created_at, expires_at
+		FROM idempotency_keys WHERE user_id = $1 AND key = $2`
+	err = r.s.Pool.QueryRow(ctx, query, record.UserID, record.Key).Scan(&existing.UserID, &existing.Key,
+		&existing.Fingerprint, &statusCode, &existing.Headers, &existing.Body, &existing.CreatedAt, &existing.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// the request holding the key failed and released it in between
+			return nil, domain.ErrIdempotencyKeyInUse
+		}
+		return nil, err
+	}
+	if statusCode != nil {
+		existing.StatusCode = *statusCode
+	}
+
+	return &existing, nil
+}
+
+func (r *IdempotencyRepo) SaveResponse(ctx context.Context, record models.IdempotencyRecord) error {
+	query := "UPDATE idempotency_keys SET status_code = $1, headers = $2, body = $3 WHERE user_id = $4 AND key = $5"
+	_, err := r.s.Pool.Exec(ctx, query, record.StatusCode, record.Headers, record.Body, record.UserID, record.Key)
+	return err
+}
+
+func (r *IdempotencyRepo) DeleteKey(ctx context.Context, userID int, key string) error {
+	query := "DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2"
+	_, err := r.s.Pool.Exec(ctx, query, userID, key)
+	return err
+}
+
+func (r *IdempotencyRepo) DeleteExpiredKeys(ctx context.Context, now time.Time) error {
+	query := "DELETE FROM idempotency_keys WHERE expires_at <= $1"
+	_, err := r.s.Pool.Exec(ctx, query, now)
+	return err
+}
@@ -0,0 +1,124 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yosakoo/task-traker/internal/domain"
+	"github.com/yosakoo/task-traker/internal/domain/models"
+	"github.com/yosakoo/task-traker/pkg/postgres"
+)
+
+type ProjectRepo struct {
+	s *postgres.Storage
+}
+
+func NewProjectRepo(pg *postgres.Storage) *ProjectRepo {
+	return &ProjectRepo{s: pg}
+}
+
+const projectColumns = "id, user_id, name, description, created_at, updated_at"
+
+func scanProject(row scanner, project *models.Project) error {
+	return row.Scan(&project.ID, &project.UserID, &project.Name, &project.Description, &project.CreatedAt, &project.UpdatedAt)
+}
+
+func projectAccessError(ctx context.Context, q querier, projectID int) error {
+	var exists bool
+	err := q.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM projects WHERE id = $1)", projectID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return domain.ErrProjectForbidden
+	}
+	return domain.ErrProjectNotFound
+}
+
+func (r *ProjectRepo) GetProjectByID(ctx context.Context, userID, projectID int) (*models.Project, error) {
+	var project models.Project
+	query := "SELECT " + projectColumns + " FROM projects WHERE id = $1 AND user_id = $2"
+	err := scanProject(r.s.Pool.QueryRow(ctx, query, projectID, userID), &project)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, projectAccessError(ctx, r.s.Pool, projectID)
+		}
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+func (r *ProjectRepo) GetUserProjects(ctx context.Context, userID int) ([]models.Project, error) {
+	query := "SELECT " + projectColumns + " FROM projects WHERE user_id = $1 ORDER BY id"
+	return r.queryProjects(ctx, query, userID)
+}
+
+// GetProjectsByIDs returns the user's projects among projectIDs, others are left out.
+func (r *ProjectRepo) GetProjectsByIDs(ctx context.Context, userID int, projectIDs []int) ([]models.Project, error) {
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+	query := "SELECT " + projectColumns + " FROM projects WHERE id = ANY($1) AND user_id = $2 ORDER BY id"
+	return r.queryProjects(ctx, query, projectIDs, userID)
+}
+
+func (r *ProjectRepo) queryProjects(ctx context.Context, query string, args ...any) ([]models.Project, error) {
+	var projects []models.Project
+	rows, err := r.s.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var project models.Project
+		if err := scanProject(rows, &project); err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+func (r *ProjectRepo) CreateProject(ctx context.Context, userID int, project models.Project) (int, error) {
+	var projectID int
+	query := "INSERT INTO projects (user_id, name, description, created_at, updated_at) VALUES ($1, $2, $3, $4, $4) RETURNING id"
+	err := r.s.Pool.QueryRow(ctx, query, userID, project.Name, project.Description, project.CreatedAt).Scan(&projectID)
+	if err != nil {
+		return 0, err
+	}
+
+	return projectID, nil
+}
+
+func (r *ProjectRepo) UpdateProject(ctx context.Context, userID, projectID int, project models.Project) error {
+	query := "UPDATE projects SET name = $1, description = $2, updated_at = $3 WHERE id = $4 AND user_id = $5"
+	tag, err := r.s.Pool.Exec(ctx, query, project.Name, project.Description, project.UpdatedAt, projectID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return projectAccessError(ctx, r.s.Pool, projectID)
+	}
+
+	return nil
+}
+
+func (r *ProjectRepo) DeleteProject(ctx context.Context, userID, projectID int) error {
+	tag, err := r.s.Pool.Exec(ctx, "DELETE FROM projects WHERE id = $1 AND user_id = $2", projectID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return projectAccessError(ctx, r.s.Pool, projectID)
+	}
+
+	return nil
+}
@@ -14,24 +14,132 @@ type Users interface {
 	SetSession(ctx context.Context, userId int, refresh string, expiresAt time.Time) error
 	GetUserByRefresh(ctx context.Context, refresh string) (int, error)
 	GetUserByID(ctx context.Context, userID int) (*models.User, error) 
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	CreatePasswordReset(ctx context.Context, userID int, tokenHash string, createdAt, expiresAt time.Time) error
+	// ResetPassword returns the ID of the user whose password was reset.
+	ResetPassword(ctx context.Context, tokenHash string, password []byte, now time.Time) (int, error)
+	CreateEmailVerification(ctx context.Context, userID int, tokenHash string, createdAt, expiresAt, notBefore time.Time) error
+	// VerifyEmail returns the ID of the user whose email was verified.
+	VerifyEmail(ctx context.Context, tokenHash string, now time.Time) (int, error)
 }
 
 type Tasks interface {
-	GetTaskByID(ctx context.Context, taskID int) (*models.Task, error)
+	GetTaskByID(ctx context.Context, userID, taskID int) (*models.Task, error)
 	CreateTask(ctx context.Context, userID int, task models.Task) (int, error)
-	UpdateTask(ctx context.Context, taskID int, task models.Task) error
-	DeleteTask(ctx context.Context, taskID int) error
-	GetUserTasks(ctx context.Context, userID int) ([]models.Task, error)
+	UpdateTask(ctx context.Context, userID, taskID int, task models.Task) error
+	PatchTask(ctx context.Context, userID, taskID int, patch models.TaskPatch) error
+	// MoveTask and SetParent relink the task only while it is at version, 0
+	// relinks it whatever the version is.
+	MoveTask(ctx context.Context, userID, taskID int, projectID *int, version int) error
+	SetParent(ctx context.Context, userID, taskID int, parentID *int, version int) error
+	GetAncestors(ctx context.Context, taskID int) ([]int, error)
+	GetSubtreeDepth(ctx context.Context, taskID int) (int, error)
+	GetSubtasks(ctx context.Context, userID, parentID int) ([]models.Task, error)
+	GetTasksByIDs(ctx context.Context, userID int, taskIDs []int) ([]models.Task, error)
+	GetSubtasksOf(ctx context.Context, userID int, parentIDs []int) ([]models.Task, error)
+	GetSubtaskProgress(ctx context.Context, taskIDs []int) (map[int]models.Progress, error)
+	DeleteTask(ctx context.Context, userID, taskID, version int) error
+	GetUserTasks(ctx context.Context, userID int, filter models.TaskFilter) ([]models.Task, error)
+	GetTaskEvents(ctx context.Context, taskID int) ([]models.TaskEvent, error)
+	GetUserTaskEvents(ctx context.Context, userID int, after models.TaskEventCursor, limit int) ([]models.TaskEvent, models.TaskEventCursor, error)
+	GetTaskEventCursor(ctx context.Context, userID int, afterID int64) (models.TaskEventCursor, error)
+	GetTrash(ctx context.Context, userID int) ([]models.Task, error)
+	RestoreTask(ctx context.Context, userID, taskID int) error
+	// PurgeTask and PurgeTrash return the storage keys of attachments removed
+	// along with the tasks and their subtasks.
+	PurgeTask(ctx context.Context, userID, taskID int) ([]string, error)
+	PurgeTrash(ctx context.Context, before time.Time) ([]string, error)
+	ApplyBatch(ctx context.Context, userID int, items []models.BatchItem, atomic bool) ([]error, error)
+}
+
+type Projects interface {
+	GetProjectByID(ctx context.Context, userID, projectID int) (*models.Project, error)
+	GetUserProjects(ctx context.Context, userID int) ([]models.Project, error)
+	GetProjectsByIDs(ctx context.Context, userID int, projectIDs []int) ([]models.Project, error)
+	CreateProject(ctx context.Context, userID int, project models.Project) (int, error)
+	UpdateProject(ctx context.Context, userID, projectID int, project models.Project) error
+	DeleteProject(ctx context.Context, userID, projectID int) error
+}
+
+type Checklists interface {
+	GetItems(ctx context.Context, taskID int) ([]models.ChecklistItem, error)
+	GetItemByID(ctx context.Context, taskID, itemID int) (*models.ChecklistItem, error)
+	AddItem(ctx context.Context, taskID int, item models.ChecklistItem) (int, error)
+	UpdateItem(ctx context.Context, taskID, itemID int, item models.ChecklistItem) error
+	DeleteItem(ctx context.Context, taskID, itemID int) error
+	GetProgress(ctx context.Context, taskIDs []int) (map[int]models.Progress, error)
+}
+
+type Comments interface {
+	GetComments(ctx context.Context, taskID, afterID, limit int) ([]models.Comment, error)
+	GetCommentByID(ctx context.Context, taskID, commentID int) (*models.Comment, error)
+	CreateComment(ctx context.Context, comment models.Comment) (int, error)
+	UpdateComment(ctx context.Context, taskID, commentID int, comment models.Comment) error
+	DeleteComment(ctx context.Context, taskID, commentID int) error
+}
+
+type Attachments interface {
+	GetAttachments(ctx context.Context, taskID int) ([]models.Attachment, error)
+	GetAttachmentByID(ctx context.Context, taskID, attachmentID int) (*models.Attachment, error)
+	CreateAttachment(ctx context.Context, attachment models.Attachment) (int, error)
+	DeleteAttachment(ctx context.Context, taskID, attachmentID int) error
+}
+
+type Dependencies interface {
+	AddDependency(ctx context.Context, taskID, blockedByID int) error
+	RemoveDependency(ctx context.Context, taskID, blockedByID int) error
+	GetBlockers(ctx context.Context, taskIDs []int) (map[int][]int, error)
+	GetBlocking(ctx context.Context, taskIDs []int) (map[int][]int, error)
+	GetOpenBlockers(ctx context.Context, taskID int) ([]int, error)
+}
+
+type Tags interface {
+	GetTagByID(ctx context.Context, userID, tagID int) (*models.Tag, error)
+	GetUserTags(ctx context.Context, userID int) ([]models.Tag, error)
+	GetTasksTags(ctx context.Context, taskIDs []int) (map[int][]models.Tag, error)
+	CreateTag(ctx context.Context, userID int, tag models.Tag) (int, error)
+	UpdateTag(ctx context.Context, userID, tagID int, tag models.Tag) error
+	DeleteTag(ctx context.Context, userID, tagID int) error
+	AttachTag(ctx context.Context, taskID, tagID int) error
+	DetachTag(ctx context.Context, taskID, tagID int) error
+}
+
+type Workflows interface {
+	GetWorkflow(ctx context.Context, userID int) (*models.Workflow, error)
+	SetWorkflow(ctx context.Context, userID int, wf models.Workflow) error
+}
+
+type IdempotencyKeys interface {
+	ClaimKey(ctx context.Context, record models.IdempotencyRecord) (*models.IdempotencyRecord, error)
+	SaveResponse(ctx context.Context, record models.IdempotencyRecord) error
+	DeleteKey(ctx context.Context, userID int, key string) error
+	DeleteExpiredKeys(ctx context.Context, now time.Time) error
 }
 
 type Repositories struct{
-	Users Users
-	Tasks Tasks
+	Users        Users
+	Tasks        Tasks
+	Projects     Projects
+	Tags         Tags
+	Checklists   Checklists
+	Comments     Comments
+	Attachments  Attachments
+	Dependencies Dependencies
+	Workflows    Workflows
+	Idempotency  IdempotencyKeys
 }
 
 func NewRepositories(pool *postgres.Storage) *Repositories{
 	return &Repositories{
-		Users: NewUserRepo(pool),
-		Tasks: NewTaskRepo(pool),
+		Users:        NewUserRepo(pool),
+		Tasks:        NewTaskRepo(pool),
+		Projects:     NewProjectRepo(pool),
+		Tags:         NewTagRepo(pool),
+		Checklists:   NewChecklistRepo(pool),
+		Comments:     NewCommentRepo(pool),
+		Attachments:  NewAttachmentRepo(pool),
+		Dependencies: NewDependencyRepo(pool),
+		Workflows:    NewWorkflowRepo(pool),
+		Idempotency:  NewIdempotencyRepo(pool),
 	}
 }
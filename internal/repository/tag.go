@@ -0,0 +1,167 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/yosakoo/task-traker/internal/domain"
+	"github.com/yosakoo/task-traker/internal/domain/models"
+	"github.com/yosakoo/task-traker/pkg/postgres"
+)
+
+const uniqueViolation = "23505"
+
+type TagRepo struct {
+	s *postgres.Storage
+}
+
+func NewTagRepo(pg *postgres.Storage) *TagRepo {
+	return &TagRepo{s: pg}
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+func tagAccessError(ctx context.Context, q querier, tagID int) error {
+	var exists bool
+	err := q.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM tags WHERE id = $1)", tagID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return domain.ErrTagForbidden
+	}
+	return domain.ErrTagNotFound
+}
+
+func (r *TagRepo) GetTagByID(ctx context.Context, userID, tagID int) (*models.Tag, error) {
+	var tag models.Tag
+	query := "SELECT id, user_id, name, color FROM tags WHERE id = $1 AND user_id = $2"
+	err := r.s.Pool.QueryRow(ctx, query, tagID, userID).Scan(&tag.ID, &tag.UserID, &tag.Name, &tag.Color)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, tagAccessError(ctx, r.s.Pool, tagID)
+		}
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+func (r *TagRepo) GetUserTags(ctx context.Context, userID int) ([]models.Tag, error) {
+	var tags []models.Tag
+	rows, err := r.s.Pool.Query(ctx, "SELECT id, user_id, name, color FROM tags WHERE user_id = $1 ORDER BY name", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.ID, &tag.UserID, &tag.Name, &tag.Color); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// GetTasksTags loads the tags of several tasks at once, keyed by task ID.
+func (r *TagRepo) GetTasksTags(ctx context.Context, taskIDs []int) (map[int][]models.Tag, error) {
+	tags := make(map[int][]models.Tag, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return tags, nil
+	}
+
+	query := `SELECT tt.task_id, g.id, g.user_id, g.name, g.color FROM task_tags tt
+		JOIN tags g ON g.id = tt.tag_id WHERE tt.task_id = ANY($1) ORDER BY g.name`
+	rows, err := r.s.Pool.Query(ctx, query, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID int
+		var tag models.Tag
+		if err := rows.Scan(&taskID, &tag.ID, &tag.UserID, &tag.Name, &tag.Color); err != nil {
+			return nil, err
+		}
+		tags[taskID] = append(tags[taskID], tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (r *TagRepo) CreateTag(ctx context.Context, userID int, tag models.Tag) (int, error) {
+	var tagID int
+	err := r.s.Pool.QueryRow(ctx, "INSERT INTO tags (user_id, name, color) VALUES ($1, $2, $3) RETURNING id",
+		userID, tag.Name, tag.Color).Scan(&tagID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, domain.ErrTagAlreadyExists
+		}
+		return 0, err
+	}
+
+	return tagID, nil
+}
+
+func (r *TagRepo) UpdateTag(ctx context.Context, userID, tagID int, tag models.Tag) error {
+	res, err := r.s.Pool.Exec(ctx, "UPDATE tags SET name = $1, color = $2 WHERE id = $3 AND user_id = $4",
+		tag.Name, tag.Color, tagID, userID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrTagAlreadyExists
+		}
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return tagAccessError(ctx, r.s.Pool, tagID)
+	}
+
+	return nil
+}
+
+func (r *TagRepo) DeleteTag(ctx context.Context, userID, tagID int) error {
+	res, err := r.s.Pool.Exec(ctx, "DELETE FROM tags WHERE id = $1 AND user_id = $2", tagID, userID)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return tagAccessError(ctx, r.s.Pool, tagID)
+	}
+
+	return nil
+}
+
+func (r *TagRepo) AttachTag(ctx context.Context, taskID, tagID int) error {
+	query := `WITH added AS (
+			INSERT INTO task_tags (task_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING RETURNING task_id
+		)
+		UPDATE tasks SET version = version + 1 WHERE id IN (SELECT task_id FROM added)`
+	_, err := r.s.Pool.Exec(ctx, query, taskID, tagID)
+	return err
+}
+
+func (r *TagRepo) DetachTag(ctx context.Context, taskID, tagID int) error {
+	query := `WITH removed AS (
+			DELETE FROM task_tags WHERE task_id = $1 AND tag_id = $2 RETURNING task_id
+		)
+		UPDATE tasks SET version = version + 1 WHERE id IN (SELECT task_id FROM removed)`
+	_, err := r.s.Pool.Exec(ctx, query, taskID, tagID)
+	return err
+}
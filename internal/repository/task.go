@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/yosakoo/task-traker/internal/domain"
@@ -19,13 +22,43 @@ func NewTaskRepo(pg *postgres.Storage) *TaskRepo {
 	return &TaskRepo{s: pg}
 }
 
-func (r *TaskRepo) GetTaskByID(ctx context.Context, taskID int) (*models.Task, error) {
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// accessError tells apart a task that doesn't exist from a task owned by someone else.
+// Tasks in the trash count as missing.
+func accessError(ctx context.Context, q querier, taskID int) error {
+	var exists bool
+	err := q.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL)", taskID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return domain.ErrTaskForbidden
+	}
+	return domain.ErrTaskNotFound
+}
+
+const taskColumns = "id, user_id, project_id, parent_id, status, title, text, priority, due_at, completed_at, created_at, updated_at, auto_complete, recurrence, series_id, occurrence, deleted_at, version"
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row scanner, task *models.Task) error {
+	return row.Scan(&task.ID, &task.UserID, &task.ProjectID, &task.ParentID, &task.Status, &task.Title, &task.Text, &task.Priority,
+		&task.DueAt, &task.CompletedAt, &task.CreatedAt, &task.UpdatedAt, &task.AutoComplete,
+		&task.Recurrence, &task.SeriesID, &task.Occurrence, &task.DeletedAt, &task.Version)
+}
+
+func (r *TaskRepo) GetTaskByID(ctx context.Context, userID, taskID int) (*models.Task, error) {
     var task models.Task
-    query := "SELECT id, status, title, text, time FROM tasks WHERE id = $1"
-    err := r.s.Pool.QueryRow(ctx, query, taskID).Scan(&task.ID, &task.Status, &task.Title, &task.Text, &task.Time)
+    query := "SELECT " + taskColumns + " FROM tasks WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL"
+    err := scanTask(r.s.Pool.QueryRow(ctx, query, taskID, userID), &task)
     if err != nil {
         if errors.Is(err, pgx.ErrNoRows) {
-            return nil, domain.ErrTaskNotFound
+            return nil, accessError(ctx, r.s.Pool, taskID)
         }
         return nil, err
     }
@@ -33,21 +66,112 @@ func (r *TaskRepo) GetTaskByID(ctx context.Context, taskID int) (*models.Task, e
     return &task, nil
 }
 
-func (r *TaskRepo) GetUserTasks(ctx context.Context, userID int) ([]models.Task, error) {
+// sortColumns maps a sort field to its column and the type its cursor value is cast to.
+var sortColumns = map[models.TaskSortField]struct{ column, cast string }{
+	models.TaskSortID:        {"id", "integer"},
+	models.TaskSortCreatedAt: {"created_at", "timestamp"},
+	models.TaskSortUpdatedAt: {"updated_at", "timestamp"},
+	// tasks without a due date go last, as if they were due at infinity
+	models.TaskSortDueAt:    {"COALESCE(due_at, 'infinity')", "timestamp"},
+	models.TaskSortPriority: {"priority", "smallint"},
+	models.TaskSortTitle:    {"title", "text"},
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (r *TaskRepo) GetUserTasks(ctx context.Context, userID int, filter models.TaskFilter) ([]models.Task, error) {
+	sort, ok := sortColumns[filter.SortBy]
+	if !ok {
+		return nil, domain.ErrInvalidTaskFilter
+	}
+
+	where := []string{"user_id = $1", "deleted_at IS NULL"}
+	args := []any{userID}
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if filter.ProjectID != nil {
+		where = append(where, "project_id = "+arg(*filter.ProjectID))
+	}
+	if len(filter.ProjectIDs) > 0 {
+		where = append(where, "project_id = ANY("+arg(filter.ProjectIDs)+")")
+	}
+	if filter.SeriesID != nil {
+		where = append(where, "series_id = "+arg(*filter.SeriesID))
+	}
+	if filter.Status != "" {
+		where = append(where, "status = "+arg(filter.Status))
+	}
+	if filter.Search != "" {
+		p := arg("%" + likeEscaper.Replace(filter.Search) + "%")
+		where = append(where, fmt.Sprintf("(title ILIKE %s OR text ILIKE %s)", p, p))
+	}
+	if len(filter.Tags) > 0 {
+		tagged := "SELECT tt.task_id FROM task_tags tt JOIN tags g ON g.id = tt.tag_id WHERE g.user_id = $1 AND g.name = ANY(" + arg(filter.Tags) + ")"
+		if filter.TagMatch == models.TagMatchAll {
+			tagged += " GROUP BY tt.task_id HAVING COUNT(*) = " + arg(len(filter.Tags))
+		}
+		where = append(where, "id IN ("+tagged+")")
+	}
+	if filter.CreatedFrom != nil {
+		where = append(where, "created_at >= "+arg(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		where = append(where, "created_at <= "+arg(*filter.CreatedTo))
+	}
+	if filter.CompletedFrom != nil {
+		where = append(where, "completed_at >= "+arg(*filter.CompletedFrom))
+	}
+	if filter.CompletedTo != nil {
+		where = append(where, "completed_at <= "+arg(*filter.CompletedTo))
+	}
+	if filter.OverdueAt != nil {
+		where = append(where, "completed_at IS NULL AND due_at < "+arg(*filter.OverdueAt))
+	}
+
+	dir, cmp := "ASC", ">"
+	if filter.Desc {
+		dir, cmp = "DESC", "<"
+	}
+	if filter.After != nil {
+		if filter.SortBy == models.TaskSortID {
+			where = append(where, fmt.Sprintf("id %s %s", cmp, arg(filter.After.ID)))
+		} else {
+			where = append(where, fmt.Sprintf("(%s, id) %s (%s::text::%s, %s)",
+				sort.column, cmp, arg(filter.After.Value), sort.cast, arg(filter.After.ID)))
+		}
+	}
+
+	order := fmt.Sprintf("%s %s, id %s", sort.column, dir, dir)
+	if filter.SortBy == models.TaskSortID {
+		order = fmt.Sprintf("id %s", dir)
+	}
+	query := "SELECT " + taskColumns + " FROM tasks WHERE " + strings.Join(where, " AND ") + " ORDER BY " + order
+	if len(filter.ProjectIDs) > 0 {
+		// the limit is per project, rows are numbered within each one
+		query = "SELECT " + taskColumns + " FROM (SELECT " + taskColumns +
+			", ROW_NUMBER() OVER (PARTITION BY project_id ORDER BY " + order + ") AS n FROM tasks WHERE " +
+			strings.Join(where, " AND ") + ") ranked"
+		if filter.Limit > 0 {
+			query += " WHERE n <= " + arg(filter.Limit)
+		}
+		query += " ORDER BY project_id, " + order
+	} else if filter.Limit > 0 {
+		query += " LIMIT " + arg(filter.Limit)
+	}
+
     var tasks []models.Task
-    query := "SELECT id, status, title, text, time FROM tasks WHERE user_id = $1"
-    rows, err := r.s.Pool.Query(ctx, query, userID)
+    rows, err := r.s.Pool.Query(ctx, query, args...)
     if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-            return nil, domain.ErrTaskNotFound
-        }
         return nil, err
     }
     defer rows.Close()
 
     for rows.Next() {
         var task models.Task
-        err := rows.Scan(&task.ID, &task.Status, &task.Title, &task.Text, &task.Time)
+        err := scanTask(rows, &task)
         if err != nil {
             return nil, err
         }
@@ -71,7 +195,37 @@ func (r *TaskRepo) CreateTask(ctx context.Context, userID int, task models.Task)
 	defer tx.Rollback(ctx)
 
 	var taskID int
-	err = tx.QueryRow(ctx, "INSERT INTO tasks (user_id, title) VALUES ($1, $2) RETURNING id", userID, task.Title).Scan(&taskID)
+	occurrence := task.Occurrence
+	if occurrence == 0 {
+		occurrence = 1
+	}
+	query := `INSERT INTO tasks (user_id, project_id, parent_id, title, status, text, priority, due_at, auto_complete,
+		recurrence, series_id, occurrence, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $13) RETURNING id`
+	err = tx.QueryRow(ctx, query, userID, task.ProjectID, task.ParentID, task.Title, task.Status, task.Text, task.Priority, task.DueAt,
+		task.AutoComplete, task.Recurrence, task.SeriesID, occurrence, task.CreatedAt).Scan(&taskID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, domain.ErrOccurrenceExists
+		}
+		return 0, err
+	}
+
+	// a recurring task without a series starts its own
+	if task.Recurrence != nil && task.SeriesID == nil {
+		_, err = tx.Exec(ctx, "UPDATE tasks SET series_id = id WHERE id = $1", taskID)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = insertTaskEvents(ctx, tx, []models.TaskEvent{{
+		TaskID:    taskID,
+		UserID:    userID,
+		Action:    models.TaskCreated,
+		NewValue:  &task.Title,
+		CreatedAt: task.CreatedAt,
+	}})
 	if err != nil {
 		return 0, err
 	}
@@ -83,7 +237,86 @@ func (r *TaskRepo) CreateTask(ctx context.Context, userID int, task models.Task)
 	return taskID, nil
 }
 
-func (r *TaskRepo) UpdateTask(ctx context.Context, taskID int, task models.Task) error {
+// lockTask reads a task for a change within tx, the row stays locked until tx ends.
+func lockTask(ctx context.Context, tx pgx.Tx, userID, taskID int) (*models.Task, error) {
+	var task models.Task
+	query := "SELECT " + taskColumns + " FROM tasks WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL FOR UPDATE"
+	err := scanTask(tx.QueryRow(ctx, query, taskID, userID), &task)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, accessError(ctx, tx, taskID)
+		}
+		return nil, err
+	}
+	return &task, nil
+}
+
+// checkVersion fails unless the task is at the expected version, 0 expects any.
+func checkVersion(task *models.Task, expected int) error {
+	if expected != 0 && task.Version != expected {
+		return domain.ErrTaskVersionMismatch
+	}
+	return nil
+}
+
+// UpdateTask writes the task only while it is still at task.Version, 0
+// writes it whatever the version is.
+func (r *TaskRepo) UpdateTask(ctx context.Context, userID, taskID int, task models.Task) error {
+	txOptions := pgx.TxOptions{}
+
+	tx, err := r.s.Pool.BeginTx(ctx, txOptions)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	old, err := lockTask(ctx, tx, userID, taskID)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(old, task.Version); err != nil {
+		return err
+	}
+
+	query := `UPDATE tasks SET version = version + 1, title = $1, status = $2, text = $3, priority = $4, due_at = $5, completed_at = $6, updated_at = $7,
+		auto_complete = $8, recurrence = $9, series_id = COALESCE(series_id, CASE WHEN $9::text IS NOT NULL THEN id END)
+		WHERE id = $10 AND user_id = $11`
+	_, err = tx.Exec(ctx, query, task.Title, task.Status, task.Text, task.Priority, task.DueAt, task.CompletedAt, task.UpdatedAt,
+		task.AutoComplete, task.Recurrence, taskID, userID)
+	if err != nil {
+		return err
+	}
+
+	updated := *old
+	updated.Title = task.Title
+	updated.Status = task.Status
+	updated.Text = task.Text
+	updated.Priority = task.Priority
+	updated.DueAt = task.DueAt
+	updated.CompletedAt = task.CompletedAt
+	updated.AutoComplete = task.AutoComplete
+	updated.Recurrence = task.Recurrence
+	if err := insertTaskEvents(ctx, tx, taskChanges(userID, *old, updated, task.UpdatedAt)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errors.New("error committing database transaction")
+	}
+
+	return nil
+}
+
+func (r *TaskRepo) MoveTask(ctx context.Context, userID, taskID int, projectID *int, version int) error {
+	return r.relinkTask(ctx, userID, taskID, version, func(task *models.Task) { task.ProjectID = projectID })
+}
+
+func (r *TaskRepo) SetParent(ctx context.Context, userID, taskID int, parentID *int, version int) error {
+	return r.relinkTask(ctx, userID, taskID, version, func(task *models.Task) { task.ParentID = parentID })
+}
+
+// relinkTask changes the project or the parent of a task and records the change.
+func (r *TaskRepo) relinkTask(ctx context.Context, userID, taskID, version int, change func(task *models.Task)) error {
 	txOptions := pgx.TxOptions{}
 
 	tx, err := r.s.Pool.BeginTx(ctx, txOptions)
@@ -91,11 +324,172 @@ func (r *TaskRepo) UpdateTask(ctx context.Context, taskID int, task models.Task)
 		return err
 	}
 	defer tx.Rollback(ctx)
-	fmt.Println(task.Status)
-	_, err = tx.Exec(ctx, "UPDATE tasks SET title = $1,status =$2, text = $3, time = $4 WHERE id = $5", task.Title, task.Status, task.Text, task.Time, taskID)
+
+	if err := relinkTask(ctx, tx, userID, taskID, version, change); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errors.New("error committing database transaction")
+	}
+
+	return nil
+}
+
+func relinkTask(ctx context.Context, tx pgx.Tx, userID, taskID, version int, change func(task *models.Task)) error {
+	old, err := lockTask(ctx, tx, userID, taskID)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(old, version); err != nil {
+		return err
+	}
+	updated := *old
+	change(&updated)
+	updated.UpdatedAt = time.Now().UTC()
+
+	query := "UPDATE tasks SET project_id = $1, parent_id = $2, updated_at = $3, version = version + 1 WHERE id = $4 AND user_id = $5"
+	_, err = tx.Exec(ctx, query, updated.ProjectID, updated.ParentID, updated.UpdatedAt, taskID, userID)
+	if err != nil {
+		return err
+	}
+	return insertTaskEvents(ctx, tx, taskChanges(userID, *old, updated, updated.UpdatedAt))
+}
+
+// GetAncestors returns the IDs of the task's parent, grandparent and so on.
+func (r *TaskRepo) GetAncestors(ctx context.Context, taskID int) ([]int, error) {
+	query := `WITH RECURSIVE ancestors (id, parent_id, depth) AS (
+			SELECT id, parent_id, 0 FROM tasks WHERE id = $1
+			UNION ALL
+			SELECT t.id, t.parent_id, a.depth + 1 FROM tasks t JOIN ancestors a ON t.id = a.parent_id
+		)
+		SELECT id FROM ancestors WHERE depth > 0 ORDER BY depth`
+	rows, err := r.s.Pool.Query(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
+
+// GetSubtreeDepth returns how many levels of subtasks are below the task.
+func (r *TaskRepo) GetSubtreeDepth(ctx context.Context, taskID int) (int, error) {
+	query := `WITH RECURSIVE subtree (id, depth) AS (
+			SELECT id, 0 FROM tasks WHERE id = $1
+			UNION ALL
+			SELECT t.id, s.depth + 1 FROM tasks t JOIN subtree s ON t.parent_id = s.id
+		)
+		SELECT MAX(depth) FROM subtree`
+	var depth *int
+	if err := r.s.Pool.QueryRow(ctx, query, taskID).Scan(&depth); err != nil {
+		return 0, err
+	}
+	if depth == nil {
+		return 0, nil
+	}
+	return *depth, nil
+}
+
+func (r *TaskRepo) GetSubtasks(ctx context.Context, userID, parentID int) ([]models.Task, error) {
+	query := "SELECT " + taskColumns + " FROM tasks WHERE parent_id = $1 AND user_id = $2 AND deleted_at IS NULL ORDER BY id"
+	return r.queryTasks(ctx, query, parentID, userID)
+}
+
+// GetTasksByIDs returns the user's tasks among taskIDs, tasks that don't
+// exist or aren't the user's are left out.
+func (r *TaskRepo) GetTasksByIDs(ctx context.Context, userID int, taskIDs []int) ([]models.Task, error) {
+	if len(taskIDs) == 0 {
+		return nil, nil
+	}
+	query := "SELECT " + taskColumns + " FROM tasks WHERE id = ANY($1) AND user_id = $2 AND deleted_at IS NULL ORDER BY id"
+	return r.queryTasks(ctx, query, taskIDs, userID)
+}
+
+// GetSubtasksOf returns the direct subtasks of all the given tasks.
+func (r *TaskRepo) GetSubtasksOf(ctx context.Context, userID int, parentIDs []int) ([]models.Task, error) {
+	if len(parentIDs) == 0 {
+		return nil, nil
+	}
+	query := "SELECT " + taskColumns + " FROM tasks WHERE parent_id = ANY($1) AND user_id = $2 AND deleted_at IS NULL ORDER BY id"
+	return r.queryTasks(ctx, query, parentIDs, userID)
+}
+
+func (r *TaskRepo) queryTasks(ctx context.Context, query string, args ...any) ([]models.Task, error) {
+	rows, err := r.s.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []models.Task
+	for rows.Next() {
+		var task models.Task
+		if err := scanTask(rows, &task); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// GetSubtaskProgress counts direct subtasks of the given tasks and how many of them are completed.
+func (r *TaskRepo) GetSubtaskProgress(ctx context.Context, taskIDs []int) (map[int]models.Progress, error) {
+	progress := make(map[int]models.Progress, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return progress, nil
+	}
+
+	query := `SELECT parent_id, COUNT(completed_at), COUNT(*) FROM tasks
+		WHERE parent_id = ANY($1) AND deleted_at IS NULL GROUP BY parent_id`
+	rows, err := r.s.Pool.Query(ctx, query, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID int
+		var p models.Progress
+		if err := rows.Scan(&taskID, &p.Done, &p.Total); err != nil {
+			return nil, err
+		}
+		progress[taskID] = p
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return progress, nil
+}
+
+// DeleteTask moves a task and its subtasks to the trash, when version isn't 0
+// only while the task is at that version.
+func (r *TaskRepo) DeleteTask(ctx context.Context, userID, taskID, version int) error {
+	txOptions := pgx.TxOptions{}
+
+	tx, err := r.s.Pool.BeginTx(ctx, txOptions)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback(ctx)
+
+	if version != 0 {
+		task, err := lockTask(ctx, tx, userID, taskID)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(task, version); err != nil {
+			return err
+		}
+	}
+	if err := trashTask(ctx, tx, userID, taskID); err != nil {
+		return err
+	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return errors.New("error committing database transaction")
@@ -104,7 +498,43 @@ func (r *TaskRepo) UpdateTask(ctx context.Context, taskID int, task models.Task)
 	return nil
 }
 
-func (r *TaskRepo) DeleteTask(ctx context.Context, taskID int) error {
+func trashTask(ctx context.Context, tx pgx.Tx, userID, taskID int) error {
+	// subtasks trashed before keep their own deleted_at, so they aren't
+	// restored along with this task
+	now := time.Now().UTC()
+	query := `WITH RECURSIVE subtree AS (
+			SELECT id FROM tasks WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at IS NULL
+		)
+		UPDATE tasks SET deleted_at = $3, version = version + 1 WHERE id IN (SELECT id FROM subtree)
+		RETURNING id, title`
+	rows, err := tx.Query(ctx, query, taskID, userID, now)
+	if err != nil {
+		return err
+	}
+	var events []models.TaskEvent
+	for rows.Next() {
+		e := models.TaskEvent{UserID: userID, Action: models.TaskDeleted, CreatedAt: now}
+		if err := rows.Scan(&e.TaskID, &e.OldValue); err != nil {
+			rows.Close()
+			return err
+		}
+		events = append(events, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return accessError(ctx, tx, taskID)
+	}
+
+	return insertTaskEvents(ctx, tx, events)
+}
+
+// PatchTask writes only the columns set in the patch.
+func (r *TaskRepo) PatchTask(ctx context.Context, userID, taskID int, patch models.TaskPatch) error {
 	txOptions := pgx.TxOptions{}
 
 	tx, err := r.s.Pool.BeginTx(ctx, txOptions)
@@ -113,10 +543,61 @@ func (r *TaskRepo) DeleteTask(ctx context.Context, taskID int) error {
 	}
 	defer tx.Rollback(ctx)
 
-	_, err = tx.Exec(ctx, "DELETE FROM tasks WHERE id = $1", taskID)
+	old, err := lockTask(ctx, tx, userID, taskID)
 	if err != nil {
 		return err
 	}
+	if err := checkVersion(old, patch.Version); err != nil {
+		return err
+	}
+
+	args := []any{patch.UpdatedAt}
+	set := []string{"updated_at = $1", "version = version + 1"}
+	column := func(name string, value any) {
+		args = append(args, value)
+		set = append(set, name+" = $"+strconv.Itoa(len(args)))
+	}
+	if patch.Title.Set {
+		column("title", patch.Title.Value)
+	}
+	if patch.Status.Set {
+		column("status", patch.Status.Value)
+	}
+	if patch.Text.Set {
+		column("text", patch.Text.Value)
+	}
+	if patch.Priority.Set {
+		column("priority", patch.Priority.Value)
+	}
+	if patch.DueAt.Set {
+		column("due_at", patch.DueAt.Value)
+	}
+	if patch.CompletedAt.Set {
+		column("completed_at", patch.CompletedAt.Value)
+	}
+	if patch.AutoComplete.Set {
+		column("auto_complete", patch.AutoComplete.Value)
+	}
+	if patch.Recurrence.Set {
+		column("recurrence", patch.Recurrence.Value)
+		p := "$" + strconv.Itoa(len(args))
+		set = append(set, "series_id = COALESCE(series_id, CASE WHEN "+p+"::text IS NOT NULL THEN id END)")
+	}
+	if patch.ProjectID.Set {
+		column("project_id", patch.ProjectID.Value)
+	}
+
+	args = append(args, taskID, userID)
+	query := fmt.Sprintf("UPDATE tasks SET %s WHERE id = $%d AND user_id = $%d",
+		strings.Join(set, ", "), len(args)-1, len(args))
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		return err
+	}
+
+	updated := patch.Apply(*old)
+	if err := insertTaskEvents(ctx, tx, taskChanges(userID, *old, updated, patch.UpdatedAt)); err != nil {
+		return err
+	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return errors.New("error committing database transaction")
@@ -0,0 +1,186 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yosakoo/task-traker/internal/domain/models"
+)
+
+// taskValues lists the fields of a task that show up in its history, as text.
+func taskValues(task models.Task) map[string]*string {
+	text := func(s string) *string { return &s }
+	number := func(n *int) *string {
+		if n == nil {
+			return nil
+		}
+		return text(strconv.Itoa(*n))
+	}
+	moment := func(t *time.Time) *string {
+		if t == nil {
+			return nil
+		}
+		return text(t.UTC().Format(time.RFC3339))
+	}
+
+	return map[string]*string{
+		"title":         text(task.Title),
+		"status":        text(task.Status),
+		"text":          task.Text,
+		"priority":      text(task.Priority.String()),
+		"due_at":        moment(task.DueAt),
+		"completed_at":  moment(task.CompletedAt),
+		"auto_complete": text(strconv.FormatBool(task.AutoComplete)),
+		"recurrence":    task.Recurrence,
+		"project_id":    number(task.ProjectID),
+		"parent_id":     number(task.ParentID),
+	}
+}
+
+// taskEventFields keeps events of one update in a stable order.
+var taskEventFields = []string{
+	"title", "status", "text", "priority", "due_at", "completed_at", "auto_complete", "recurrence", "project_id", "parent_id",
+}
+
+func sameValue(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// taskChanges returns an update event for each field that differs between old and updated.
+func taskChanges(userID int, old, updated models.Task, at time.Time) []models.TaskEvent {
+	oldValues, newValues := taskValues(old), taskValues(updated)
+	var events []models.TaskEvent
+	for _, field := range taskEventFields {
+		if sameValue(oldValues[field], newValues[field]) {
+			continue
+		}
+		events = append(events, models.TaskEvent{
+			TaskID:    old.ID,
+			UserID:    userID,
+			Action:    models.TaskUpdated,
+			Field:     &field,
+			OldValue:  oldValues[field],
+			NewValue:  newValues[field],
+			CreatedAt: at,
+		})
+	}
+	return events
+}
+
+func insertTaskEvents(ctx context.Context, tx pgx.Tx, events []models.TaskEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	rows := make([][]any, 0, len(events))
+	for _, e := range events {
+		rows = append(rows, []any{e.TaskID, e.UserID, string(e.Action), e.Field, e.OldValue, e.NewValue, e.CreatedAt})
+	}
+	_, err := tx.CopyFrom(ctx, pgx.Identifier{"task_events"},
+		[]string{"task_id", "user_id", "action", "field", "old_value", "new_value", "created_at"},
+		pgx.CopyFromRows(rows))
+	return err
+}
+
+const taskEventColumns = "id, task_id, user_id, action, field, old_value, new_value, created_at"
+
+// GetTaskEvents returns the history of a task, oldest first.
+func (r *TaskRepo) GetTaskEvents(ctx context.Context, taskID int) ([]models.TaskEvent, error) {
+	query := "SELECT " + taskEventColumns + " FROM task_events WHERE task_id = $1 ORDER BY created_at, id"
+	return r.queryTaskEvents(ctx, query, taskID)
+}
+
+// visibleEvents limits events to transactions older than any still running,
+// no more events can show up below that line.
+const visibleEvents = "xid < pg_snapshot_xmin(pg_current_snapshot())"
+
+// GetUserTaskEvents returns events of the tasks of a user after the cursor,
+// in the order they became visible, and the cursor to go on from. Events of
+// a transaction only show up once every older transaction is finished.
+func (r *TaskRepo) GetUserTaskEvents(ctx context.Context, userID int, after models.TaskEventCursor,
+	limit int) ([]models.TaskEvent, models.TaskEventCursor, error) {
+	// pgx doesn't know xid8, so it goes back and forth as text
+	query := "SELECT " + taskEventColumns + ", xid::text FROM task_events " +
+		"WHERE user_id = $1 AND (xid, id) > ($2::text::xid8, $3) AND " + visibleEvents +
+		" ORDER BY xid, id LIMIT $4"
+	rows, err := r.s.Pool.Query(ctx, query, userID, strconv.FormatUint(after.XID, 10), after.ID, limit)
+	if err != nil {
+		return nil, after, err
+	}
+	defer rows.Close()
+
+	var events []models.TaskEvent
+	for rows.Next() {
+		var e models.TaskEvent
+		var xid string
+		err := rows.Scan(&e.ID, &e.TaskID, &e.UserID, &e.Action, &e.Field, &e.OldValue, &e.NewValue, &e.CreatedAt, &xid)
+		if err != nil {
+			return nil, after, err
+		}
+		if after.XID, err = strconv.ParseUint(xid, 10, 64); err != nil {
+			return nil, after, err
+		}
+		after.ID = e.ID
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, after, err
+	}
+
+	return events, after, nil
+}
+
+// GetTaskEventCursor returns the cursor just past the event afterID of the
+// user, or past the latest visible event when afterID is 0. Unknown IDs
+// resume from the closest event before them.
+func (r *TaskRepo) GetTaskEventCursor(ctx context.Context, userID int, afterID int64) (models.TaskEventCursor, error) {
+	query := "SELECT xid::text, id FROM task_events WHERE user_id = $1 AND " + visibleEvents +
+		" ORDER BY xid DESC, id DESC LIMIT 1"
+	args := []any{userID}
+	if afterID > 0 {
+		query = "SELECT xid::text, id FROM task_events WHERE user_id = $1 AND id <= $2 ORDER BY id DESC LIMIT 1"
+		args = append(args, afterID)
+	}
+
+	var cursor models.TaskEventCursor
+	var xid string
+	err := r.s.Pool.QueryRow(ctx, query, args...).Scan(&xid, &cursor.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.TaskEventCursor{}, nil
+		}
+		return cursor, err
+	}
+	cursor.XID, err = strconv.ParseUint(xid, 10, 64)
+	return cursor, err
+}
+
+func (r *TaskRepo) queryTaskEvents(ctx context.Context, query string, args ...any) ([]models.TaskEvent, error) {
+	var events []models.TaskEvent
+	rows, err := r.s.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e models.TaskEvent
+		err := rows.Scan(&e.ID, &e.TaskID, &e.UserID, &e.Action, &e.Field, &e.OldValue, &e.NewValue, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
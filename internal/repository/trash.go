@@ -0,0 +1,182 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yosakoo/task-traker/internal/domain"
+	"github.com/yosakoo/task-traker/internal/domain/models"
+)
+
+// GetTrash returns trashed tasks of a user, most recently deleted first.
+// Subtasks trashed along with their parent are left out.
+func (r *TaskRepo) GetTrash(ctx context.Context, userID int) ([]models.Task, error) {
+	query := "SELECT " + taskColumns + ` FROM tasks t WHERE user_id = $1 AND deleted_at IS NOT NULL
+		AND NOT EXISTS (SELECT 1 FROM tasks p WHERE p.id = t.parent_id AND p.deleted_at IS NOT NULL)
+		ORDER BY deleted_at DESC, id DESC`
+	rows, err := r.s.Pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []models.Task
+	for rows.Next() {
+		var task models.Task
+		if err := scanTask(rows, &task); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// RestoreTask takes a task out of the trash together with the subtasks
+// trashed with it. A task whose parent is still in the trash becomes a
+// top-level task.
+func (r *TaskRepo) RestoreTask(ctx context.Context, userID, taskID int) error {
+	txOptions := pgx.TxOptions{}
+
+	tx, err := r.s.Pool.BeginTx(ctx, txOptions)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var deletedAt time.Time
+	query := "SELECT deleted_at FROM tasks WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL FOR UPDATE"
+	err = tx.QueryRow(ctx, query, taskID, userID).Scan(&deletedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrTaskNotFound
+		}
+		return err
+	}
+
+	query = `UPDATE tasks t SET parent_id = NULL FROM tasks p
+		WHERE t.id = $1 AND p.id = t.parent_id AND p.deleted_at IS NOT NULL`
+	if _, err := tx.Exec(ctx, query, taskID); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	query = `WITH RECURSIVE subtree AS (
+			SELECT id FROM tasks WHERE id = $1
+			UNION ALL
+			SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at = $2
+		)
+		UPDATE tasks SET deleted_at = NULL, updated_at = $3, version = version + 1 WHERE id IN (SELECT id FROM subtree)
+		RETURNING id, title`
+	rows, err := tx.Query(ctx, query, taskID, deletedAt, now)
+	if err != nil {
+		return err
+	}
+	var events []models.TaskEvent
+	for rows.Next() {
+		e := models.TaskEvent{UserID: userID, Action: models.TaskRestored, CreatedAt: now}
+		if err := rows.Scan(&e.TaskID, &e.NewValue); err != nil {
+			rows.Close()
+			return err
+		}
+		events = append(events, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if err := insertTaskEvents(ctx, tx, events); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errors.New("error committing database transaction")
+	}
+
+	return nil
+}
+
+// purgeQuery collects attachments of the tasks matched by roots and of all
+// their subtasks, which go away through ON DELETE CASCADE.
+const purgeQuery = `WITH RECURSIVE doomed AS (
+		%s
+		UNION
+		SELECT t.id FROM tasks t JOIN doomed d ON t.parent_id = d.id
+	)
+	SELECT storage_key FROM task_attachments WHERE task_id IN (SELECT id FROM doomed)`
+
+// PurgeTask removes a trashed task for good and returns the storage keys of
+// its attachments.
+func (r *TaskRepo) PurgeTask(ctx context.Context, userID, taskID int) ([]string, error) {
+	txOptions := pgx.TxOptions{}
+
+	tx, err := r.s.Pool.BeginTx(ctx, txOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	roots := "SELECT id FROM tasks WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL"
+	rows, err := tx.Query(ctx, fmt.Sprintf(purgeQuery, roots), taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := tx.Exec(ctx, "DELETE FROM tasks WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL", taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, domain.ErrTaskNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, errors.New("error committing database transaction")
+	}
+
+	return keys, nil
+}
+
+// PurgeTrash removes all tasks trashed before the given time and returns
+// the storage keys of their attachments.
+func (r *TaskRepo) PurgeTrash(ctx context.Context, before time.Time) ([]string, error) {
+	txOptions := pgx.TxOptions{}
+
+	tx, err := r.s.Pool.BeginTx(ctx, txOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	roots := "SELECT id FROM tasks WHERE deleted_at < $1"
+	rows, err := tx.Query(ctx, fmt.Sprintf(purgeQuery, roots), before)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM tasks WHERE deleted_at < $1", before); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, errors.New("error committing database transaction")
+	}
+
+	return keys, nil
+}
@@ -3,7 +3,6 @@ package repo
 import (
 	"context"
 	"errors"
-	"fmt"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -27,7 +26,6 @@ func (r *UserRepo) GetUserByCredentials(ctx context.Context, email string, passw
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrUserNotFound
 		}
-        fmt.Println(err)
 		return nil, err
 	}
 	return &user, nil
@@ -38,7 +36,10 @@ func (r *UserRepo) GetUserByRefresh(ctx context.Context, refresh string) (int, e
 	var expiresAt time.Time
 	err := r.s.Pool.QueryRow(ctx, "SELECT user_id, expires_at FROM refresh_tokens WHERE refresh_token = $1", refresh).Scan(&userId, &expiresAt)
 	if err != nil {
-        
+		// the token was replaced by a newer one or revoked
+		if errors.Is(err, pgx.ErrNoRows) {
+			return userId, domain.ErrTokenExpired
+		}
 		return userId, err
 	}
 	if time.Now().After(expiresAt) {
@@ -50,8 +51,21 @@ func (r *UserRepo) GetUserByRefresh(ctx context.Context, refresh string) (int, e
 
 func (r *UserRepo) GetUserByID(ctx context.Context, userID int) (*models.User, error) {
 	var user models.User
-	query := "SELECT id, name, email FROM users WHERE id = $1"
-	err := r.s.Pool.QueryRow(ctx, query, userID).Scan(&user.ID, &user.Name, &user.Email)
+	query := "SELECT id, name, email, created_at, verified_at FROM users WHERE id = $1"
+	err := r.s.Pool.QueryRow(ctx, query, userID).Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt, &user.VerifiedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *UserRepo) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	query := "SELECT id, name, email FROM users WHERE email = $1"
+	err := r.s.Pool.QueryRow(ctx, query, email).Scan(&user.ID, &user.Name, &user.Email)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrUserNotFound
@@ -79,7 +93,7 @@ func (r *UserRepo) AddUser(ctx context.Context, user models.User) (int, error) {
 		return 0, err
 	}
 
-	err = tx.QueryRow(ctx, "INSERT INTO users (email,name, pass_hash) VALUES ($1, $2,$3) RETURNING id", user.Email, user.Name, user.Password).Scan(&userId)
+	err = tx.QueryRow(ctx, "INSERT INTO users (email,name, pass_hash, created_at) VALUES ($1, $2,$3, $4) RETURNING id", user.Email, user.Name, user.Password, user.CreatedAt).Scan(&userId)
 	if err != nil {
 		return 0, err
 	}
@@ -116,3 +130,121 @@ func (r *UserRepo) SetSession(ctx context.Context, userId int, refresh string, e
 
 	return nil
 }
+
+func (r *UserRepo) CreatePasswordReset(ctx context.Context, userID int, tokenHash string, createdAt, expiresAt time.Time) error {
+	query := "INSERT INTO password_resets (user_id, token_hash, created_at, expires_at) VALUES ($1, $2, $3, $4)"
+	_, err := r.s.Pool.Exec(ctx, query, userID, tokenHash, createdAt, expiresAt)
+	return err
+}
+
+// ResetPassword sets the password of the user the reset token was created
+// for. The token and the user's other reset tokens are used up and the
+// user's refresh token is revoked, so other sessions have to sign in again.
+func (r *UserRepo) ResetPassword(ctx context.Context, tokenHash string, password []byte, now time.Time) (int, error) {
+	tx, err := r.s.Pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var userID int
+	query := "SELECT user_id FROM password_resets WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2 FOR UPDATE"
+	err = tx.QueryRow(ctx, query, tokenHash, now).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domain.ErrInvalidResetToken
+		}
+		return 0, err
+	}
+
+	if _, err := tx.Exec(ctx, "UPDATE users SET pass_hash = $1 WHERE id = $2", password, userID); err != nil {
+		return 0, err
+	}
+	query = "UPDATE password_resets SET used_at = $1 WHERE user_id = $2 AND used_at IS NULL"
+	if _, err := tx.Exec(ctx, query, now, userID); err != nil {
+		return 0, err
+	}
+	query = "UPDATE refresh_tokens SET refresh_token = NULL, expires_at = NULL WHERE user_id = $1"
+	if _, err := tx.Exec(ctx, query, userID); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+// CreateEmailVerification stores a verification token of the user. It fails
+// with domain.ErrVerificationThrottled if another token was created after
+// notBefore and with domain.ErrEmailAlreadyVerified if there is nothing left
+// to verify.
+func (r *UserRepo) CreateEmailVerification(ctx context.Context, userID int, tokenHash string, createdAt, expiresAt, notBefore time.Time) error {
+	tx, err := r.s.Pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// locking the user makes concurrent requests wait for each other, so
+	// the throttle can't be passed twice at once
+	var verifiedAt *time.Time
+	err = tx.QueryRow(ctx, "SELECT verified_at FROM users WHERE id = $1 FOR UPDATE", userID).Scan(&verifiedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+		return err
+	}
+	if verifiedAt != nil {
+		return domain.ErrEmailAlreadyVerified
+	}
+
+	var recent bool
+	query := "SELECT EXISTS (SELECT 1 FROM email_verifications WHERE user_id = $1 AND created_at > $2)"
+	if err := tx.QueryRow(ctx, query, userID, notBefore).Scan(&recent); err != nil {
+		return err
+	}
+	if recent {
+		return domain.ErrVerificationThrottled
+	}
+
+	query = "INSERT INTO email_verifications (user_id, token_hash, created_at, expires_at) VALUES ($1, $2, $3, $4)"
+	if _, err := tx.Exec(ctx, query, userID, tokenHash, createdAt, expiresAt); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// VerifyEmail marks the email of the user the token was created for as
+// verified and uses up all of the user's verification tokens.
+func (r *UserRepo) VerifyEmail(ctx context.Context, tokenHash string, now time.Time) (int, error) {
+	tx, err := r.s.Pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var userID int
+	query := "SELECT user_id FROM email_verifications WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2 FOR UPDATE"
+	err = tx.QueryRow(ctx, query, tokenHash, now).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domain.ErrInvalidVerifyToken
+		}
+		return 0, err
+	}
+
+	if _, err := tx.Exec(ctx, "UPDATE users SET verified_at = $1 WHERE id = $2 AND verified_at IS NULL", now, userID); err != nil {
+		return 0, err
+	}
+	query = "UPDATE email_verifications SET used_at = $1 WHERE user_id = $2 AND used_at IS NULL"
+	if _, err := tx.Exec(ctx, query, now, userID); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
@@ -0,0 +1,112 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yosakoo/task-traker/internal/domain"
+	"github.com/yosakoo/task-traker/internal/domain/models"
+	"github.com/yosakoo/task-traker/pkg/postgres"
+)
+
+type WorkflowRepo struct {
+	s *postgres.Storage
+}
+
+func NewWorkflowRepo(pg *postgres.Storage) *WorkflowRepo {
+	return &WorkflowRepo{s: pg}
+}
+
+func (r *WorkflowRepo) GetWorkflow(ctx context.Context, userID int) (*models.Workflow, error) {
+	var wf models.Workflow
+
+	rows, err := r.s.Pool.Query(ctx, "SELECT name, category FROM workflow_statuses WHERE user_id = $1 ORDER BY position", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status models.WorkflowStatus
+		if err := rows.Scan(&status.Name, &status.Category); err != nil {
+			return nil, err
+		}
+		wf.Statuses = append(wf.Statuses, status)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(wf.Statuses) == 0 {
+		return nil, domain.ErrWorkflowNotFound
+	}
+
+	rows, err = r.s.Pool.Query(ctx, "SELECT from_status, to_status FROM workflow_transitions WHERE user_id = $1", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t models.WorkflowTransition
+		if err := rows.Scan(&t.From, &t.To); err != nil {
+			return nil, err
+		}
+		wf.Transitions = append(wf.Transitions, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &wf, nil
+}
+
+func (r *WorkflowRepo) SetWorkflow(ctx context.Context, userID int, wf models.Workflow) error {
+	txOptions := pgx.TxOptions{}
+
+	tx, err := r.s.Pool.BeginTx(ctx, txOptions)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	names := make([]string, 0, len(wf.Statuses))
+	for _, status := range wf.Statuses {
+		names = append(names, status.Name)
+	}
+
+	var orphan string
+	err = tx.QueryRow(ctx, "SELECT status FROM tasks WHERE user_id = $1 AND status <> ALL($2) LIMIT 1", userID, names).Scan(&orphan)
+	if err == nil {
+		return domain.ErrWorkflowStatusInUse
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, "DELETE FROM workflow_statuses WHERE user_id = $1", userID)
+	if err != nil {
+		return err
+	}
+
+	for i, status := range wf.Statuses {
+		_, err = tx.Exec(ctx, "INSERT INTO workflow_statuses (user_id, name, category, position) VALUES ($1, $2, $3, $4)",
+			userID, status.Name, status.Category, i)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, t := range wf.Transitions {
+		_, err = tx.Exec(ctx, "INSERT INTO workflow_transitions (user_id, from_status, to_status) VALUES ($1, $2, $3)",
+			userID, t.From, t.To)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errors.New("error committing database transaction")
+	}
+
+	return nil
+}
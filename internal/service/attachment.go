@@ -0,0 +1,169 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/yosakoo/task-traker/internal/domain"
+	"github.com/yosakoo/task-traker/internal/domain/models"
+	"github.com/yosakoo/task-traker/internal/repository"
+	"github.com/yosakoo/task-traker/pkg/blob"
+)
+
+// sniffLen is how much of a file http.DetectContentType looks at.
+const sniffLen = 512
+
+type AttachmentService struct {
+	repo         repo.Attachments
+	tasks        repo.Tasks
+	blobs        BlobStore
+	maxSize      int64
+	allowedTypes []string
+}
+
+func NewAttachmentService(repo repo.Attachments, tasks repo.Tasks, blobs BlobStore, maxSize int64,
+	allowedTypes []string) *AttachmentService {
+	return &AttachmentService{
+		repo:         repo,
+		tasks:        tasks,
+		blobs:        blobs,
+		maxSize:      maxSize,
+		allowedTypes: allowedTypes,
+	}
+}
+
+func newAttachmentOut(a models.Attachment) AttachmentOut {
+	return AttachmentOut{
+		ID:          a.ID,
+		Filename:    a.Filename,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+func (s *AttachmentService) GetAttachments(ctx context.Context, userID, taskID int) ([]AttachmentOut, error) {
+	if _, err := s.tasks.GetTaskByID(ctx, userID, taskID); err != nil {
+		return nil, err
+	}
+	attachments, err := s.repo.GetAttachments(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachmentsOut := make([]AttachmentOut, 0, len(attachments))
+	for _, a := range attachments {
+		attachmentsOut = append(attachmentsOut, newAttachmentOut(a))
+	}
+	return attachmentsOut, nil
+}
+
+func (s *AttachmentService) OpenAttachment(ctx context.Context, userID, taskID, attachmentID int) (AttachmentOut, io.ReadCloser, error) {
+	if _, err := s.tasks.GetTaskByID(ctx, userID, taskID); err != nil {
+		return AttachmentOut{}, nil, err
+	}
+	a, err := s.repo.GetAttachmentByID(ctx, taskID, attachmentID)
+	if err != nil {
+		return AttachmentOut{}, nil, err
+	}
+	body, err := s.blobs.Get(ctx, a.StorageKey)
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) {
+			return AttachmentOut{}, nil, domain.ErrAttachmentNotFound
+		}
+		return AttachmentOut{}, nil, err
+	}
+	return newAttachmentOut(*a), body, nil
+}
+
+// contentType tells the type from the first bytes of a file. The declared
+// type is only trusted when the contents say nothing.
+func contentType(head []byte, declared string) string {
+	detected, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if detected != "application/octet-stream" {
+		return detected
+	}
+	if declared, _, err := mime.ParseMediaType(declared); err == nil {
+		return declared
+	}
+	return detected
+}
+
+func newStorageKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	name := hex.EncodeToString(b)
+	return path.Join("tasks", name[:2], name), nil
+}
+
+func (s *AttachmentService) UploadAttachment(ctx context.Context, userID, taskID int, input AttachmentInput) (AttachmentOut, error) {
+	if _, err := s.tasks.GetTaskByID(ctx, userID, taskID); err != nil {
+		return AttachmentOut{}, err
+	}
+
+	body := bufio.NewReaderSize(input.Body, sniffLen)
+	head, err := body.Peek(sniffLen)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return AttachmentOut{}, err
+	}
+	mediaType := contentType(head, input.ContentType)
+	if !slices.Contains(s.allowedTypes, mediaType) {
+		return AttachmentOut{}, domain.ErrAttachmentType
+	}
+
+	key, err := newStorageKey()
+	if err != nil {
+		return AttachmentOut{}, err
+	}
+	// one byte over the limit is enough to know the file is too large
+	size, err := s.blobs.Put(ctx, key, io.LimitReader(body, s.maxSize+1))
+	if err != nil {
+		return AttachmentOut{}, err
+	}
+	if size > s.maxSize {
+		s.blobs.Delete(ctx, key)
+		return AttachmentOut{}, domain.ErrAttachmentTooLarge
+	}
+
+	a := models.Attachment{
+		TaskID:      taskID,
+		UserID:      userID,
+		Filename:    filepath.Base(input.Filename),
+		ContentType: mediaType,
+		Size:        size,
+		StorageKey:  key,
+		CreatedAt:   time.Now().UTC(),
+	}
+	a.ID, err = s.repo.CreateAttachment(ctx, a)
+	if err != nil {
+		s.blobs.Delete(ctx, key)
+		return AttachmentOut{}, err
+	}
+	return newAttachmentOut(a), nil
+}
+
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, userID, taskID, attachmentID int) error {
+	if _, err := s.tasks.GetTaskByID(ctx, userID, taskID); err != nil {
+		return err
+	}
+	a, err := s.repo.GetAttachmentByID(ctx, taskID, attachmentID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteAttachment(ctx, taskID, attachmentID); err != nil {
+		return err
+	}
+	return s.blobs.Delete(ctx, a.StorageKey)
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yosakoo/task-traker/internal/domain"
+	"github.com/yosakoo/task-traker/internal/domain/models"
+)
+
+const maxBulkItems = 500
+
+// bulkErrors are the errors whose text is shown in bulk results, anything
+// else is reported as an internal error.
+var bulkErrors = []error{
+	domain.ErrTaskNotFound,
+	domain.ErrTaskForbidden,
+	domain.ErrInvalidStatus,
+	domain.ErrStatusTransition,
+	domain.ErrTaskBlocked,
+	domain.ErrTagNotFound,
+	domain.ErrTagForbidden,
+	domain.ErrProjectNotFound,
+	domain.ErrProjectForbidden,
+	domain.ErrBatchAborted,
+}
+
+func bulkError(err error) string {
+	for _, known := range bulkErrors {
+		if errors.Is(err, known) {
+			return known.Error()
+		}
+	}
+	return "internal error"
+}
+
+// bulkState keeps what a batch has looked up so far. Tasks reflect the
+// changes of earlier items, so that two status changes of one task are
+// checked against each other.
+type bulkState struct {
+	workflow *models.Workflow
+	tasks    map[int]*models.Task
+	tags     map[int]error
+	projects map[int]error
+}
+
+func (s *TaskService) bulkTask(ctx context.Context, userID int, state *bulkState, taskID int) (*models.Task, error) {
+	if task, ok := state.tasks[taskID]; ok {
+		return task, nil
+	}
+	task, err := s.repo.GetTaskByID(ctx, userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	state.tasks[taskID] = task
+	return task, nil
+}
+
+// prepareBulkItem checks one item of a batch. The task it returns is set when
+// the item completes the task, it is needed once the batch is applied.
+func (s *TaskService) prepareBulkItem(ctx context.Context, userID int, state *bulkState, op BulkOperation,
+	taskID int) (models.BatchItem, *models.Task, error) {
+	item := models.BatchItem{Action: models.BatchAction(op.Op), TaskID: taskID}
+	switch item.Action {
+	case models.BatchSetStatus:
+		task, err := s.bulkTask(ctx, userID, state, taskID)
+		if err != nil {
+			return item, nil, err
+		}
+		if state.workflow == nil {
+			wf, _, err := loadWorkflow(ctx, s.workflows, userID)
+			if err != nil {
+				return item, nil, err
+			}
+			state.workflow = &wf
+		}
+		status, ok := state.workflow.Status(op.Status)
+		if !ok {
+			return item, nil, domain.ErrInvalidStatus
+		}
+		if op.Status != task.Status && !state.workflow.CanTransition(task.Status, op.Status) {
+			return item, nil, domain.ErrStatusTransition
+		}
+
+		wasDone := task.CompletedAt != nil
+		done := status.Category == models.CategoryDone
+		if done && !wasDone && !op.Force {
+			blockers, err := s.dependencies.GetOpenBlockers(ctx, taskID)
+			if err != nil {
+				return item, nil, err
+			}
+			if len(blockers) > 0 {
+				return item, nil, domain.ErrTaskBlocked
+			}
+		}
+
+		item.Status = op.Status
+		item.CompletedAt = task.CompletedAt
+		if !done {
+			item.CompletedAt = nil
+		} else if !wasDone {
+			now := time.Now().UTC()
+			item.CompletedAt = &now
+		}
+		task.Status = item.Status
+		task.CompletedAt = item.CompletedAt
+		if done && !wasDone {
+			completed := *task
+			return item, &completed, nil
+		}
+		return item, nil, nil
+
+	case models.BatchSetTags:
+		for _, tagID := range op.TagIDs {
+			err, ok := state.tags[tagID]
+			if !ok {
+				_, err = s.tags.GetTagByID(ctx, userID, tagID)
+				state.tags[tagID] = err
+			}
+			if err != nil {
+				return item, nil, err
+			}
+		}
+		item.TagIDs = op.TagIDs
+		return item, nil, nil
+
+	case models.BatchMoveProject:
+		if op.ProjectID != nil {
+			err, ok := state.projects[*op.ProjectID]
+			if !ok {
+				_, err = s.projects.GetProjectByID(ctx, userID, *op.ProjectID)
+				state.projects[*op.ProjectID] = err
+			}
+			if err != nil {
+				return item, nil, err
+			}
+		}
+		item.ProjectID = op.ProjectID
+		return item, nil, nil
+
+	case models.BatchDelete:
+		return item, nil, nil
+	}
+	return item, nil, domain.ErrInvalidBatch
+}
+
+// BulkUpdate applies operations to many tasks in one transaction. Items are
+// checked first, so in atomic mode nothing reaches the database if any of
+// them is invalid.
+func (s *TaskService) BulkUpdate(ctx context.Context, userID int, input BulkInput) ([]BulkItemResult, error) {
+	total := 0
+	for _, op := range input.Operations {
+		total += len(op.TaskIDs)
+	}
+	if total == 0 || total > maxBulkItems {
+		return nil, domain.ErrInvalidBatch
+	}
+
+	state := &bulkState{
+		tasks:    make(map[int]*models.Task),
+		tags:     make(map[int]error),
+		projects: make(map[int]error),
+	}
+	results := make([]BulkItemResult, 0, total)
+	var items []models.BatchItem
+	// completed and positions line up with items
+	var completed []*models.Task
+	var positions []int
+	invalid := false
+	for i, op := range input.Operations {
+		for _, taskID := range op.TaskIDs {
+			results = append(results, BulkItemResult{Operation: i, TaskID: taskID})
+			item, task, err := s.prepareBulkItem(ctx, userID, state, op, taskID)
+			if err != nil {
+				if errors.Is(err, domain.ErrInvalidBatch) {
+					return nil, err
+				}
+				results[len(results)-1].Error = bulkError(err)
+				invalid = true
+				continue
+			}
+			items = append(items, item)
+			completed = append(completed, task)
+			positions = append(positions, len(results)-1)
+		}
+	}
+
+	if input.Atomic && invalid {
+		for _, pos := range positions {
+			results[pos].Error = domain.ErrBatchAborted.Error()
+		}
+		return results, nil
+	}
+	if len(items) == 0 {
+		return results, nil
+	}
+
+	errs, err := s.repo.ApplyBatch(ctx, userID, items, input.Atomic)
+	if err != nil {
+		return nil, err
+	}
+	for j, err := range errs {
+		if err != nil {
+			results[positions[j]].Error = bulkError(err)
+			continue
+		}
+		results[positions[j]].OK = true
+	}
+
+	for j, task := range completed {
+		if task == nil || errs[j] != nil {
+			continue
+		}
+		// the batch is applied, its results stand whatever happens here
+		if err := s.afterCompletion(ctx, userID, *task); err != nil {
+			s.log.Error(fmt.Errorf("TaskService - BulkUpdate - afterCompletion: %w", err))
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/yosakoo/task-traker/internal/domain/models"
+	"github.com/yosakoo/task-traker/internal/repository"
+)
+
+type ChecklistService struct {
+	repo  repo.Checklists
+	tasks repo.Tasks
+}
+
+func NewChecklistService(repo repo.Checklists, tasks repo.Tasks) *ChecklistService {
+	return &ChecklistService{
+		repo:  repo,
+		tasks: tasks,
+	}
+}
+
+func newChecklistItemOut(item models.ChecklistItem) ChecklistItemOut {
+	return ChecklistItemOut{
+		ID:        item.ID,
+		Text:      item.Text,
+		Done:      item.Done,
+		Position:  item.Position,
+		CreatedAt: item.CreatedAt,
+	}
+}
+
+func (s *ChecklistService) GetItems(ctx context.Context, userID, taskID int) ([]ChecklistItemOut, error) {
+	if _, err := s.tasks.GetTaskByID(ctx, userID, taskID); err != nil {
+		return nil, err
+	}
+	items, err := s.repo.GetItems(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	itemsOut := make([]ChecklistItemOut, 0, len(items))
+	for _, item := range items {
+		itemsOut = append(itemsOut, newChecklistItemOut(item))
+	}
+	return itemsOut, nil
+}
+
+func (s *ChecklistService) AddItem(ctx context.Context, userID, taskID int, input ChecklistItemInput) (int, error) {
+	if _, err := s.tasks.GetTaskByID(ctx, userID, taskID); err != nil {
+		return 0, err
+	}
+	return s.repo.AddItem(ctx, taskID, models.ChecklistItem{
+		TaskID:    taskID,
+		Text:      input.Text,
+		Done:      input.Done,
+		CreatedAt: time.Now().UTC(),
+	})
+}
+
+func (s *ChecklistService) UpdateItem(ctx context.Context, userID, taskID, itemID int, input ChecklistItemUpdateInput) error {
+	if _, err := s.tasks.GetTaskByID(ctx, userID, taskID); err != nil {
+		return err
+	}
+	item, err := s.repo.GetItemByID(ctx, taskID, itemID)
+	if err != nil {
+		return err
+	}
+	if input.Text != nil {
+		item.Text = *input.Text
+	}
+	if input.Done != nil {
+		item.Done = *input.Done
+	}
+	if input.Position != nil {
+		item.Position = *input.Position
+	}
+	return s.repo.UpdateItem(ctx, taskID, itemID, *item)
+}
+
+func (s *ChecklistService) DeleteItem(ctx context.Context, userID, taskID, itemID int) error {
+	if _, err := s.tasks.GetTaskByID(ctx, userID, taskID); err != nil {
+		return err
+	}
+	return s.repo.DeleteItem(ctx, taskID, itemID)
+}
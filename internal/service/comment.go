@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/yosakoo/task-traker/internal/domain"
+	"github.com/yosakoo/task-traker/internal/domain/models"
+	"github.com/yosakoo/task-traker/internal/repository"
+)
+
+const (
+	defaultCommentsLimit = 50
+	maxCommentsLimit     = 200
+	commentCursorSort    = "comment"
+)
+
+type CommentService struct {
+	repo  repo.Comments
+	tasks repo.Tasks
+	users repo.Users
+}
+
+func NewCommentService(repo repo.Comments, tasks repo.Tasks, users repo.Users) *CommentService {
+	return &CommentService{
+		repo:  repo,
+		tasks: tasks,
+		users: users,
+	}
+}
+
+// commentsOut attaches authors to comments, each author is loaded once.
+func (s *CommentService) commentsOut(ctx context.Context, comments []models.Comment) ([]CommentOut, error) {
+	authors := make(map[int]CommentAuthor)
+	commentsOut := make([]CommentOut, 0, len(comments))
+	for _, comment := range comments {
+		author, ok := authors[comment.AuthorID]
+		if !ok {
+			user, err := s.users.GetUserByID(ctx, comment.AuthorID)
+			if err != nil {
+				return nil, err
+			}
+			author = CommentAuthor{ID: user.ID, Name: user.Name}
+			authors[comment.AuthorID] = author
+		}
+		commentsOut = append(commentsOut, CommentOut{
+			ID:        comment.ID,
+			Author:    author,
+			Text:      comment.Text,
+			CreatedAt: comment.CreatedAt,
+			EditedAt:  comment.EditedAt,
+		})
+	}
+	return commentsOut, nil
+}
+
+func (s *CommentService) commentOut(ctx context.Context, comment models.Comment) (CommentOut, error) {
+	commentsOut, err := s.commentsOut(ctx, []models.Comment{comment})
+	if err != nil {
+		return CommentOut{}, err
+	}
+	return commentsOut[0], nil
+}
+
+func (s *CommentService) GetComments(ctx context.Context, userID, taskID int, input CommentListInput) (CommentList, error) {
+	if _, err := s.tasks.GetTaskByID(ctx, userID, taskID); err != nil {
+		return CommentList{}, err
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultCommentsLimit
+	}
+	if limit > maxCommentsLimit {
+		limit = maxCommentsLimit
+	}
+
+	afterID := 0
+	if input.Cursor != "" {
+		c, err := decodeCursor(input.Cursor)
+		if err != nil {
+			return CommentList{}, err
+		}
+		if c.Sort != commentCursorSort {
+			return CommentList{}, domain.ErrInvalidCursor
+		}
+		afterID = c.ID
+	}
+
+	// one extra comment tells whether there is a next page
+	comments, err := s.repo.GetComments(ctx, taskID, afterID, limit+1)
+	if err != nil {
+		return CommentList{}, err
+	}
+
+	var list CommentList
+	if len(comments) > limit {
+		comments = comments[:limit]
+		last := comments[len(comments)-1]
+		list.NextCursor = encodeCursor(taskCursor{Sort: commentCursorSort, ID: last.ID})
+	}
+	list.Comments, err = s.commentsOut(ctx, comments)
+	if err != nil {
+		return CommentList{}, err
+	}
+	return list, nil
+}
+
+func (s *CommentService) CreateComment(ctx context.Context, userID, taskID int, input CommentInput) (CommentOut, error) {
+	if _, err := s.tasks.GetTaskByID(ctx, userID, taskID); err != nil {
+		return CommentOut{}, err
+	}
+
+	comment := models.Comment{
+		TaskID:    taskID,
+		AuthorID:  userID,
+		Text:      input.Text,
+		CreatedAt: time.Now().UTC(),
+	}
+	commentID, err := s.repo.CreateComment(ctx, comment)
+	if err != nil {
+		return CommentOut{}, err
+	}
+	comment.ID = commentID
+	return s.commentOut(ctx, comment)
+}
+
+// ownComment loads a comment the user is allowed to change.
+func (s *CommentService) ownComment(ctx context.Context, userID, taskID, commentID int) (*models.Comment, error) {
+	if _, err := s.tasks.GetTaskByID(ctx, userID, taskID); err != nil {
+		return nil, err
+	}
+	comment, err := s.repo.GetCommentByID(ctx, taskID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != userID {
+		return nil, domain.ErrCommentForbidden
+	}
+	return comment, nil
+}
+
+func (s *CommentService) UpdateComment(ctx context.Context, userID, taskID, commentID int, input CommentInput) (CommentOut, error) {
+	comment, err := s.ownComment(ctx, userID, taskID, commentID)
+	if err != nil {
+		return CommentOut{}, err
+	}
+
+	now := time.Now().UTC()
+	comment.Text = input.Text
+	comment.EditedAt = &now
+	if err := s.repo.UpdateComment(ctx, taskID, commentID, *comment); err != nil {
+		return CommentOut{}, err
+	}
+	return s.commentOut(ctx, *comment)
+}
+
+func (s *CommentService) DeleteComment(ctx context.Context, userID, taskID, commentID int) error {
+	if _, err := s.ownComment(ctx, userID, taskID, commentID); err != nil {
+		return err
+	}
+	return s.repo.DeleteComment(ctx, taskID, commentID)
+}
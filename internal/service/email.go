@@ -22,7 +22,6 @@ func (s *EmailService) SendEmail(ctx context.Context, email *Email) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal email data: %w", err)
 	}
-	fmt.Print(data)
 	if err := s.queueConn.PublishMessage(ctx, "application/json", data); err != nil {
 		return fmt.Errorf("failed to send email message to queue: %w", err)
 	}
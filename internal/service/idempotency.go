@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/yosakoo/task-traker/internal/domain"
+	"github.com/yosakoo/task-traker/internal/domain/models"
+	"github.com/yosakoo/task-traker/internal/repository"
+)
+
+type IdempotencyService struct {
+	repo repo.IdempotencyKeys
+	ttl  time.Duration
+}
+
+func NewIdempotencyService(repo repo.IdempotencyKeys, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		repo: repo,
+		ttl:  ttl,
+	}
+}
+
+func (s *IdempotencyService) Begin(ctx context.Context, userID int, key, fingerprint string) (*StoredResponse, error) {
+	now := time.Now().UTC()
+	existing, err := s.repo.ClaimKey(ctx, models.IdempotencyRecord{
+		UserID:      userID,
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, nil
+	}
+
+	if existing.Fingerprint != fingerprint {
+		return nil, domain.ErrIdempotencyKeyReused
+	}
+	if existing.StatusCode == 0 {
+		return nil, domain.ErrIdempotencyKeyInUse
+	}
+
+	return &StoredResponse{
+		StatusCode: existing.StatusCode,
+		Headers:    existing.Headers,
+		Body:       existing.Body,
+	}, nil
+}
+
+func (s *IdempotencyService) Finish(ctx context.Context, userID int, key string, response StoredResponse) error {
+	return s.repo.SaveResponse(ctx, models.IdempotencyRecord{
+		UserID:     userID,
+		Key:        key,
+		StatusCode: response.StatusCode,
+		Headers:    response.Headers,
+		Body:       response.Body,
+	})
+}
+
+func (s *IdempotencyService) Release(ctx context.Context, userID int, key string) error {
+	return s.repo.DeleteKey(ctx, userID, key)
+}
+
+func (s *IdempotencyService) Purge(ctx context.Context) error {
+	return s.repo.DeleteExpiredKeys(ctx, time.Now().UTC())
+}
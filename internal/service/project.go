@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/yosakoo/task-traker/internal/domain/models"
+	"github.com/yosakoo/task-traker/internal/repository"
+)
+
+type ProjectService struct {
+	repo  repo.Projects
+	tasks Tasks
+}
+
+func NewProjectService(repo repo.Projects, tasks Tasks) *ProjectService {
+	return &ProjectService{
+		repo:  repo,
+		tasks: tasks,
+	}
+}
+
+func newProjectOut(project models.Project) ProjectOut {
+	projectOut := ProjectOut{
+		ID:        project.ID,
+		Name:      project.Name,
+		CreatedAt: project.CreatedAt,
+		UpdatedAt: project.UpdatedAt,
+	}
+	if project.Description != nil {
+		projectOut.Description = *project.Description
+	}
+	return projectOut
+}
+
+func (s *ProjectService) GetProjectByID(ctx context.Context, userID, projectID int) (ProjectOut, error) {
+	project, err := s.repo.GetProjectByID(ctx, userID, projectID)
+	if err != nil {
+		return ProjectOut{}, err
+	}
+	return newProjectOut(*project), nil
+}
+
+func (s *ProjectService) GetUserProjects(ctx context.Context, userID int) ([]ProjectOut, error) {
+	projects, err := s.repo.GetUserProjects(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return projectsOut(projects), nil
+}
+
+func (s *ProjectService) GetProjectsByIDs(ctx context.Context, userID int, projectIDs []int) ([]ProjectOut, error) {
+	projects, err := s.repo.GetProjectsByIDs(ctx, userID, projectIDs)
+	if err != nil {
+		return nil, err
+	}
+	return projectsOut(projects), nil
+}
+
+func projectsOut(projects []models.Project) []ProjectOut {
+	projectsOut := make([]ProjectOut, 0, len(projects))
+	for _, project := range projects {
+		projectsOut = append(projectsOut, newProjectOut(project))
+	}
+	return projectsOut
+}
+
+func (s *ProjectService) GetProjectTasks(ctx context.Context, userID, projectID int, input TaskListInput) (TaskList, error) {
+	if _, err := s.repo.GetProjectByID(ctx, userID, projectID); err != nil {
+		return TaskList{}, err
+	}
+	input.ProjectID = &projectID
+	return s.tasks.GetUserTasks(ctx, userID, input)
+}
+
+func (s *ProjectService) CreateProject(ctx context.Context, userID int, input ProjectInput) (int, error) {
+	project := models.Project{
+		UserID:    userID,
+		Name:      input.Name,
+		CreatedAt: time.Now().UTC(),
+	}
+	if input.Description != "" {
+		project.Description = &input.Description
+	}
+	return s.repo.CreateProject(ctx, userID, project)
+}
+
+func (s *ProjectService) UpdateProject(ctx context.Context, userID, projectID int, input ProjectInput) error {
+	project := models.Project{
+		Name:      input.Name,
+		UpdatedAt: time.Now().UTC(),
+	}
+	if input.Description != "" {
+		project.Description = &input.Description
+	}
+	return s.repo.UpdateProject(ctx, userID, projectID, project)
+}
+
+func (s *ProjectService) DeleteProject(ctx context.Context, userID, projectID int) error {
+	return s.repo.DeleteProject(ctx, userID, projectID)
+}
@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"io"
 	"time"
 
+	"github.com/yosakoo/task-traker/internal/domain/models"
 	"github.com/yosakoo/task-traker/internal/repository"
 	"github.com/yosakoo/task-traker/pkg/auth"
 	"github.com/yosakoo/task-traker/pkg/hash"
@@ -23,9 +25,10 @@ type UserSignInInput struct {
 }
 
 type AuthUser struct {
-	ID    int    `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Email      string     `json:"email"`
+	VerifiedAt *time.Time `json:"verified_at"`
 }
 type Tokens struct {
 	AccessToken  string
@@ -37,36 +40,410 @@ type Users interface {
 	SignIn(ctx context.Context, input UserSignInInput) (Tokens, error)
 	RefreshTokens(ctx context.Context, refreshToken string) (Tokens, error)
 	GetUserByID(ctx context.Context, userID int) (AuthUser, error)
+	// ForgotPassword emails a link to reset the password to the user with
+	// the email, if there is one.
+	ForgotPassword(ctx context.Context, email string) error
+	// ResetPassword sets a new password with the token of a reset link and
+	// signs the user out everywhere.
+	ResetPassword(ctx context.Context, token, password string) error
+	// VerifyEmail marks the email of the user as verified with the token of
+	// a verification link.
+	VerifyEmail(ctx context.Context, token string) error
+	// ResendVerification emails a new verification link to the user, at
+	// most once per AccountOptions.VerificationResendInterval.
+	ResendVerification(ctx context.Context, userID int) error
+}
+
+// AccountOptions tune how users verify and recover their accounts.
+type AccountOptions struct {
+	// PasswordResetTTL is how long a password reset link can be used.
+	PasswordResetTTL time.Duration
+	// PasswordResetURL is the page that resets passwords, the token is
+	// added to it as the token query parameter.
+	PasswordResetURL string
+	// VerificationTTL is how long an email verification link can be used.
+	VerificationTTL time.Duration
+	// VerificationURL is the page that verifies emails, the token is added
+	// to it as the token query parameter.
+	VerificationURL string
+	// VerificationResendInterval is how long users wait before another
+	// verification link is sent.
+	VerificationResendInterval time.Duration
+	// UnverifiedGracePeriod is how long after signing up users can create
+	// tasks without verifying their email. Zero doesn't restrict them.
+	UnverifiedGracePeriod time.Duration
+}
+
+// mayCreateTasks tells whether the account policy lets the user create tasks.
+func (o AccountOptions) mayCreateTasks(user *models.User, now time.Time) bool {
+	if user.VerifiedAt != nil || o.UnverifiedGracePeriod <= 0 {
+		return true
+	}
+	return now.Before(user.CreatedAt.Add(o.UnverifiedGracePeriod))
 }
 
 type TaskInput struct {
-	Title  string
-	Status string
-	Text   string
-	Time   time.Time
+	ProjectID    *int
+	ParentID     *int
+	Title        string
+	Status       string
+	Text         string
+	Priority     string
+	DueAt        *time.Time
+	AutoComplete *bool
+	// Recurrence is an RRULE, nil keeps the current one and "" removes it.
+	Recurrence *string
+	// Force completes a task even if it is blocked by unfinished tasks.
+	Force bool
+	// Version makes the update go through only while the task is at that
+	// version, 0 updates any version.
+	Version int
+}
+
+// TaskPatchInput changes only the fields that are set, a set field without
+// a value is cleared.
+type TaskPatchInput struct {
+	Title        models.Nullable[string]
+	Status       models.Nullable[string]
+	Text         models.Nullable[string]
+	Priority     models.Nullable[string]
+	DueAt        models.Nullable[time.Time]
+	AutoComplete models.Nullable[bool]
+	Recurrence   models.Nullable[string]
+	ProjectID    models.Nullable[int]
+	Force        bool
+	Version      int
 }
 
 type TaskOut struct {
-	ID     int       `json:"id"`
-	Status string    `json:"status"`
-	Title  string    `json:"title"`
-	Text   string    `json:"text"`
-	Time   time.Time `json:"time"`
+	ID           int         `json:"id"`
+	ProjectID    *int        `json:"project_id"`
+	ParentID     *int        `json:"parent_id"`
+	Status       string      `json:"status"`
+	Title        string      `json:"title"`
+	Text         string      `json:"text"`
+	Priority     string      `json:"priority"`
+	DueAt        *time.Time  `json:"due_at"`
+	IsOverdue    bool        `json:"is_overdue"`
+	CompletedAt  *time.Time  `json:"completed_at"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+	AutoComplete bool        `json:"auto_complete"`
+	Progress     ProgressOut `json:"progress"`
+	Tags         []TagOut    `json:"tags"`
+	BlockedBy    []int       `json:"blocked_by"`
+	Blocking     []int       `json:"blocking"`
+	Recurrence   string      `json:"recurrence,omitempty"`
+	SeriesID     *int        `json:"series_id"`
+	Occurrence   int         `json:"occurrence"`
+	DeletedAt    *time.Time  `json:"deleted_at,omitempty"`
+	Version      int         `json:"version"`
+}
+
+// ProgressOut sums up subtasks and checklist items of a task, Text reads like "3/5".
+type ProgressOut struct {
+	Done  int    `json:"done"`
+	Total int    `json:"total"`
+	Text  string `json:"text"`
+}
+
+type TaskListInput struct {
+	ProjectID     *int
+	SeriesID      *int
+	Status        string
+	Search        string
+	Tags          []string
+	TagMatch      string
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	CompletedFrom *time.Time
+	CompletedTo   *time.Time
+	Overdue       bool
+	SortBy        string
+	Order         string
+	Limit         int
+	Cursor        string
+}
+
+// TaskGroup holds the tasks in one workflow status.
+type TaskGroup struct {
+	Status   string    `json:"status"`
+	Category string    `json:"category"`
+	Tasks    []TaskOut `json:"tasks"`
+}
+
+type TaskList struct {
+	Groups     []TaskGroup
+	NextCursor string
+}
+
+// TaskEventOut is one entry of a task's history, Field and the values are
+// only set for updates.
+type TaskEventOut struct {
+	ID        int64     `json:"id"`
+	TaskID    int       `json:"task_id"`
+	UserID    int       `json:"user_id"`
+	Action    string    `json:"action"`
+	Field     *string   `json:"field,omitempty"`
+	OldValue  *string   `json:"old_value"`
+	NewValue  *string   `json:"new_value"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BulkOperation applies one change to each of TaskIDs. Op is one of
+// update_status, set_tags, move_project and delete.
+type BulkOperation struct {
+	Op        string
+	TaskIDs   []int
+	Status    string
+	Force     bool
+	TagIDs    []int
+	ProjectID *int
+}
+
+type BulkInput struct {
+	Operations []BulkOperation
+	// Atomic applies either every item or none of them.
+	Atomic bool
+}
+
+// BulkItemResult reports how one operation went for one task.
+type BulkItemResult struct {
+	Operation int    `json:"operation"`
+	TaskID    int    `json:"task_id"`
+	OK        bool   `json:"ok"`
+	Error     string `json:"error,omitempty"`
 }
 
 type Tasks interface {
-	GetTaskByID(ctx context.Context, taskID int) (TaskOut, error)
-	GetUserTasks(ctx context.Context, userID int) (completedTasks []TaskOut, pendingTasks []TaskOut, err error)
+	GetTaskByID(ctx context.Context, userID, taskID int) (TaskOut, error)
+	GetUserTasks(ctx context.Context, userID int, input TaskListInput) (TaskList, error)
 	CreateTask(ctx context.Context, userID int, input TaskInput) (int, error)
-	UpdateTask(ctx context.Context, taskID int, input TaskInput) error
-	DeleteTask(ctx context.Context, taskID int) error
+	UpdateTask(ctx context.Context, userID, taskID int, input TaskInput) error
+	PatchTask(ctx context.Context, userID, taskID int, input TaskPatchInput) error
+	// MoveTask and SetParent relink the task, see TaskInput.Version for version.
+	MoveTask(ctx context.Context, userID, taskID int, projectID *int, version int) error
+	SetParent(ctx context.Context, userID, taskID int, parentID *int, version int) error
+	GetSubtasks(ctx context.Context, userID, taskID int) ([]TaskOut, error)
+	// GetTasksByIDs, GetSubtasksOf and GetTasksOfProjects load many tasks at
+	// once, tasks the user can't see are left out.
+	GetTasksByIDs(ctx context.Context, userID int, taskIDs []int) ([]TaskOut, error)
+	GetSubtasksOf(ctx context.Context, userID int, parentIDs []int) ([]TaskOut, error)
+	GetTasksOfProjects(ctx context.Context, userID int, projectIDs []int, input TaskListInput) (map[int]TaskList, error)
+	AddDependency(ctx context.Context, userID, taskID, blockedByID int) error
+	RemoveDependency(ctx context.Context, userID, taskID, blockedByID int) error
+	// DeleteTask moves the task to the trash, see TaskInput.Version for version.
+	DeleteTask(ctx context.Context, userID, taskID, version int) error
+	GetTaskHistory(ctx context.Context, userID, taskID int) ([]TaskEventOut, error)
+	// WatchTasks calls send with the events of the user's tasks after the
+	// event afterID as they happen, until ctx is done or send fails. afterID 0
+	// starts with events that happen from now on. Events come in the order
+	// they were committed, which isn't always the order of their IDs.
+	WatchTasks(ctx context.Context, userID int, afterID int64, send func([]TaskEventOut) error) error
+	BulkUpdate(ctx context.Context, userID int, input BulkInput) ([]BulkItemResult, error)
+}
+
+type ChecklistItemInput struct {
+	Text string
+	Done bool
+}
+
+// ChecklistItemUpdateInput changes only the fields that are set.
+type ChecklistItemUpdateInput struct {
+	Text     *string
+	Done     *bool
+	Position *int
+}
+
+type ChecklistItemOut struct {
+	ID        int       `json:"id"`
+	Text      string    `json:"text"`
+	Done      bool      `json:"done"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Checklists interface {
+	GetItems(ctx context.Context, userID, taskID int) ([]ChecklistItemOut, error)
+	AddItem(ctx context.Context, userID, taskID int, input ChecklistItemInput) (int, error)
+	UpdateItem(ctx context.Context, userID, taskID, itemID int, input ChecklistItemUpdateInput) error
+	DeleteItem(ctx context.Context, userID, taskID, itemID int) error
+}
+
+type Trash interface {
+	GetTrash(ctx context.Context, userID int) ([]TaskOut, error)
+	RestoreTask(ctx context.Context, userID, taskID int) error
+	// DeleteTask removes a trashed task for good.
+	DeleteTask(ctx context.Context, userID, taskID int) error
+	// Purge removes tasks that have been in the trash for longer than retention.
+	Purge(ctx context.Context, retention time.Duration) error
+}
+
+// StoredResponse is a response kept for replaying requests with the same Idempotency-Key.
+type StoredResponse struct {
+	StatusCode int
+	Headers    map[string][]string
+	Body       []byte
+}
+
+type Idempotency interface {
+	// Begin claims key for a request with the given fingerprint. If the key
+	// was used before, the response stored for it is returned instead.
+	Begin(ctx context.Context, userID int, key, fingerprint string) (*StoredResponse, error)
+	// Finish stores the response to the request that claimed key.
+	Finish(ctx context.Context, userID int, key string, response StoredResponse) error
+	// Release frees key so the request can be retried.
+	Release(ctx context.Context, userID int, key string) error
+	// Purge removes expired keys.
+	Purge(ctx context.Context) error
+}
+
+type CommentInput struct {
+	Text string
+}
+
+type CommentListInput struct {
+	Limit  int
+	Cursor string
 }
 
+type CommentAuthor struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type CommentOut struct {
+	ID        int           `json:"id"`
+	Author    CommentAuthor `json:"author"`
+	Text      string        `json:"text"`
+	CreatedAt time.Time     `json:"created_at"`
+	EditedAt  *time.Time    `json:"edited_at"`
+}
+
+type CommentList struct {
+	Comments   []CommentOut
+	NextCursor string
+}
+
+type Comments interface {
+	GetComments(ctx context.Context, userID, taskID int, input CommentListInput) (CommentList, error)
+	CreateComment(ctx context.Context, userID, taskID int, input CommentInput) (CommentOut, error)
+	UpdateComment(ctx context.Context, userID, taskID, commentID int, input CommentInput) (CommentOut, error)
+	DeleteComment(ctx context.Context, userID, taskID, commentID int) error
+}
+
+// BlobStore keeps attachment contents, see pkg/blob for implementations.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type AttachmentInput struct {
+	Filename string
+	// ContentType is what the client sent, it is only used when the type
+	// can't be told from the contents.
+	ContentType string
+	Body        io.Reader
+}
+
+type AttachmentOut struct {
+	ID          int       `json:"id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type Attachments interface {
+	GetAttachments(ctx context.Context, userID, taskID int) ([]AttachmentOut, error)
+	// OpenAttachment returns the attachment and its contents, the caller closes the reader.
+	OpenAttachment(ctx context.Context, userID, taskID, attachmentID int) (AttachmentOut, io.ReadCloser, error)
+	UploadAttachment(ctx context.Context, userID, taskID int, input AttachmentInput) (AttachmentOut, error)
+	DeleteAttachment(ctx context.Context, userID, taskID, attachmentID int) error
+}
+
+type ProjectInput struct {
+	Name        string
+	Description string
+}
+
+type ProjectOut struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type Projects interface {
+	GetProjectByID(ctx context.Context, userID, projectID int) (ProjectOut, error)
+	GetUserProjects(ctx context.Context, userID int) ([]ProjectOut, error)
+	// GetProjectsByIDs leaves out projects the user can't see.
+	GetProjectsByIDs(ctx context.Context, userID int, projectIDs []int) ([]ProjectOut, error)
+	GetProjectTasks(ctx context.Context, userID, projectID int, input TaskListInput) (TaskList, error)
+	CreateProject(ctx context.Context, userID int, input ProjectInput) (int, error)
+	UpdateProject(ctx context.Context, userID, projectID int, input ProjectInput) error
+	DeleteProject(ctx context.Context, userID, projectID int) error
+}
+
+
+type TagInput struct {
+	Name  string
+	Color string
+}
+
+// TagUpdateInput changes only the fields that are set.
+type TagUpdateInput struct {
+	Name  *string
+	Color *string
+}
+
+type TagOut struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type Tags interface {
+	GetUserTags(ctx context.Context, userID int) ([]TagOut, error)
+	CreateTag(ctx context.Context, userID int, input TagInput) (int, error)
+	UpdateTag(ctx context.Context, userID, tagID int, input TagUpdateInput) error
+	DeleteTag(ctx context.Context, userID, tagID int) error
+	AttachTag(ctx context.Context, userID, taskID, tagID int) error
+	DetachTag(ctx context.Context, userID, taskID, tagID int) error
+}
+
+type WorkflowStatus struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+type WorkflowTransition struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type WorkflowInput struct {
+	Statuses    []WorkflowStatus
+	Transitions []WorkflowTransition
+}
+
+type WorkflowOut struct {
+	Statuses    []WorkflowStatus     `json:"statuses"`
+	Transitions []WorkflowTransition `json:"transitions"`
+	IsDefault   bool                 `json:"is_default"`
+}
+
+type Workflows interface {
+	GetWorkflow(ctx context.Context, userID int) (WorkflowOut, error)
+	SetWorkflow(ctx context.Context, userID int, input WorkflowInput) error
+}
 
 type Email struct {
     Subject string `json:"subject"`
     Body    string `json:"body"`
-    To      string `json"to"`
+    To      string `json:"to"`
 }
 
 type Emails interface{
@@ -74,9 +451,17 @@ type Emails interface{
 }
 
 type Services struct {
-    Users  Users
-    Tasks  Tasks
-    Emails Emails
+    Users      Users
+    Tasks      Tasks
+    Projects   Projects
+    Tags       Tags
+    Checklists Checklists
+    Comments    Comments
+    Attachments Attachments
+    Workflows  Workflows
+    Trash      Trash
+    Idempotency Idempotency
+    Emails     Emails
 }
 
 type Deps struct {
@@ -88,6 +473,13 @@ type Deps struct {
     AccessTokenTTL  time.Duration
     RefreshTokenTTL time.Duration
     EmailService    Emails 
+    Blobs           BlobStore
+    // MaxAttachmentSize is in bytes, AttachmentTypes lists allowed MIME types.
+    MaxAttachmentSize int64
+    AttachmentTypes   []string
+    // IdempotencyTTL is how long responses to requests with an Idempotency-Key are kept.
+    IdempotencyTTL time.Duration
+    Accounts       AccountOptions
 }
 
 
@@ -95,8 +487,28 @@ type Deps struct {
 func NewServices(deps Deps) *Services {
 	
     emailService := NewEmailService(deps.QueueConn)
-    userService :=  NewUserService(deps.Repos.Users, deps.Log, deps.Hasher, deps.TokenManager, emailService, deps.AccessTokenTTL, deps.RefreshTokenTTL)
-    taskService :=  NewTaskService(deps.Repos.Tasks)
-    return &Services{Users: userService, Tasks: taskService, Emails: emailService}
+    userService :=  NewUserService(deps.Repos.Users, deps.Log, deps.Hasher, deps.TokenManager, emailService, deps.AccessTokenTTL, deps.RefreshTokenTTL, deps.Accounts)
+    taskService :=  NewTaskService(deps.Repos.Tasks, deps.Repos.Projects, deps.Repos.Tags, deps.Repos.Checklists, deps.Repos.Dependencies, deps.Repos.Workflows, deps.Repos.Users, deps.Accounts, deps.Log)
+    projectService := NewProjectService(deps.Repos.Projects, taskService)
+    tagService := NewTagService(deps.Repos.Tags, deps.Repos.Tasks)
+    checklistService := NewChecklistService(deps.Repos.Checklists, deps.Repos.Tasks)
+    commentService := NewCommentService(deps.Repos.Comments, deps.Repos.Tasks, deps.Repos.Users)
+    attachmentService := NewAttachmentService(deps.Repos.Attachments, deps.Repos.Tasks, deps.Blobs, deps.MaxAttachmentSize, deps.AttachmentTypes)
+    workflowService := NewWorkflowService(deps.Repos.Workflows)
+    trashService := NewTrashService(deps.Repos.Tasks, taskService, deps.Blobs, deps.Log)
+    idempotencyService := NewIdempotencyService(deps.Repos.Idempotency, deps.IdempotencyTTL)
+    return &Services{
+        Users:      userService,
+        Tasks:      taskService,
+        Projects:   projectService,
+        Tags:       tagService,
+        Checklists: checklistService,
+        Comments:   commentService,
+        Attachments: attachmentService,
+        Workflows:  workflowService,
+        Trash:      trashService,
+        Idempotency: idempotencyService,
+        Emails:     emailService,
+    }
 }
 
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/yosakoo/task-traker/internal/domain"
+	"github.com/yosakoo/task-traker/internal/domain/models"
+	"github.com/yosakoo/task-traker/internal/repository"
+)
+
+const defaultTagColor = "#808080"
+
+type TagService struct {
+	repo  repo.Tags
+	tasks repo.Tasks
+}
+
+func NewTagService(repo repo.Tags, tasks repo.Tasks) *TagService {
+	return &TagService{
+		repo:  repo,
+		tasks: tasks,
+	}
+}
+
+// normalizeTagName lets clients write tags the way people do, "#backend" and "backend" are the same tag.
+func normalizeTagName(name string) string {
+	return strings.TrimPrefix(strings.TrimSpace(name), "#")
+}
+
+func uniqueTagNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	unique := make([]string, 0, len(names))
+	for _, name := range names {
+		name = normalizeTagName(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		unique = append(unique, name)
+	}
+	return unique
+}
+
+func newTagOut(tag models.Tag) TagOut {
+	return TagOut{
+		ID:    tag.ID,
+		Name:  tag.Name,
+		Color: tag.Color,
+	}
+}
+
+func (s *TagService) GetUserTags(ctx context.Context, userID int) ([]TagOut, error) {
+	tags, err := s.repo.GetUserTags(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tagsOut := make([]TagOut, 0, len(tags))
+	for _, tag := range tags {
+		tagsOut = append(tagsOut, newTagOut(tag))
+	}
+	return tagsOut, nil
+}
+
+func (s *TagService) CreateTag(ctx context.Context, userID int, input TagInput) (int, error) {
+	tag := models.Tag{
+		UserID: userID,
+		Name:   normalizeTagName(input.Name),
+		Color:  input.Color,
+	}
+	if tag.Name == "" {
+		return 0, domain.ErrInvalidTagName
+	}
+	if tag.Color == "" {
+		tag.Color = defaultTagColor
+	}
+	return s.repo.CreateTag(ctx, userID, tag)
+}
+
+func (s *TagService) UpdateTag(ctx context.Context, userID, tagID int, input TagUpdateInput) error {
+	tag, err := s.repo.GetTagByID(ctx, userID, tagID)
+	if err != nil {
+		return err
+	}
+	if input.Name != nil {
+		tag.Name = normalizeTagName(*input.Name)
+		if tag.Name == "" {
+			return domain.ErrInvalidTagName
+		}
+	}
+	if input.Color != nil {
+		tag.Color = *input.Color
+	}
+	return s.repo.UpdateTag(ctx, userID, tagID, *tag)
+}
+
+func (s *TagService) DeleteTag(ctx context.Context, userID, tagID int) error {
+	return s.repo.DeleteTag(ctx, userID, tagID)
+}
+
+func (s *TagService) AttachTag(ctx context.Context, userID, taskID, tagID int) error {
+	if _, err := s.tasks.GetTaskByID(ctx, userID, taskID); err != nil {
+		return err
+	}
+	if _, err := s.repo.GetTagByID(ctx, userID, tagID); err != nil {
+		return err
+	}
+	return s.repo.AttachTag(ctx, taskID, tagID)
+}
+
+func (s *TagService) DetachTag(ctx context.Context, userID, taskID, tagID int) error {
+	if _, err := s.tasks.GetTaskByID(ctx, userID, taskID); err != nil {
+		return err
+	}
+	if _, err := s.repo.GetTagByID(ctx, userID, tagID); err != nil {
+		return err
+	}
+	return s.repo.DetachTag(ctx, taskID, tagID)
+}
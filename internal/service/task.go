@@ -2,77 +2,455 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/yosakoo/task-traker/internal/domain"
 	"github.com/yosakoo/task-traker/internal/domain/models"
 	"github.com/yosakoo/task-traker/internal/repository"
+	"github.com/yosakoo/task-traker/pkg/logger"
+	"github.com/yosakoo/task-traker/pkg/rrule"
 )
 
+// maxTaskDepth limits nesting: a task, its subtasks and their subtasks.
+const maxTaskDepth = 3
+
 type TaskService struct {
-	repo repo.Tasks
+	repo         repo.Tasks
+	projects     repo.Projects
+	tags         repo.Tags
+	checklists   repo.Checklists
+	dependencies repo.Dependencies
+	workflows    repo.Workflows
+	users        repo.Users
+	accounts     AccountOptions
+	log          *logger.Logger
 }
 
-func NewTaskService(repo repo.Tasks) *TaskService {
+func NewTaskService(repo repo.Tasks, projects repo.Projects, tags repo.Tags, checklists repo.Checklists,
+	dependencies repo.Dependencies, workflows repo.Workflows, users repo.Users, accounts AccountOptions,
+	log *logger.Logger) *TaskService {
 	return &TaskService{
-		repo: repo,
+		repo:         repo,
+		projects:     projects,
+		tags:         tags,
+		checklists:   checklists,
+		dependencies: dependencies,
+		workflows:    workflows,
+		users:        users,
+		accounts:     accounts,
+		log:          log,
 	}
 }
 
-func (s *TaskService) GetTaskByID(ctx context.Context, taskID int) (TaskOut, error) {
-	task, err := s.repo.GetTaskByID(ctx, taskID)
+func (s *TaskService) GetTaskByID(ctx context.Context, userID, taskID int) (TaskOut, error) {
+	task, err := s.repo.GetTaskByID(ctx, userID, taskID)
 	if err != nil {
 		return TaskOut{}, err
 	}
+	tasksOut, err := s.tasksOut(ctx, []models.Task{*task})
+	if err != nil {
+		return TaskOut{}, err
+	}
+	return tasksOut[0], nil
+}
+
+// tasksOut converts tasks for output, loading what they reference in batches.
+func (s *TaskService) tasksOut(ctx context.Context, tasks []models.Task) ([]TaskOut, error) {
+	ids := make([]int, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	tags, err := s.tags.GetTasksTags(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	subtasks, err := s.repo.GetSubtaskProgress(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	checklists, err := s.checklists.GetProgress(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	blockers, err := s.dependencies.GetBlockers(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	blocking, err := s.dependencies.GetBlocking(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	tasksOut := make([]TaskOut, 0, len(tasks))
+	for _, task := range tasks {
+		taskOut := newTaskOut(task)
+		for _, tag := range tags[task.ID] {
+			taskOut.Tags = append(taskOut.Tags, newTagOut(tag))
+		}
+		progress := subtasks[task.ID].Add(checklists[task.ID])
+		taskOut.Progress = ProgressOut{
+			Done:  progress.Done,
+			Total: progress.Total,
+			Text:  fmt.Sprintf("%d/%d", progress.Done, progress.Total),
+		}
+		if ids, ok := blockers[task.ID]; ok {
+			taskOut.BlockedBy = ids
+		}
+		if ids, ok := blocking[task.ID]; ok {
+			taskOut.Blocking = ids
+		}
+		tasksOut = append(tasksOut, taskOut)
+	}
+	return tasksOut, nil
+}
+
+func newTaskOut(task models.Task) TaskOut {
 	taskOut := TaskOut{
-		ID:    task.ID,
-		Status: task.Status,
-		Title: task.Title,
-		Text:  *task.Text,
-		Time:  *task.Time,
+		ID:           task.ID,
+		ProjectID:    task.ProjectID,
+		ParentID:     task.ParentID,
+		Status:       task.Status,
+		Title:        task.Title,
+		Priority:     task.Priority.String(),
+		DueAt:        task.DueAt,
+		IsOverdue:    task.IsOverdue(time.Now().UTC()),
+		CompletedAt:  task.CompletedAt,
+		CreatedAt:    task.CreatedAt,
+		UpdatedAt:    task.UpdatedAt,
+		AutoComplete: task.AutoComplete,
+		Tags:         []TagOut{},
+		BlockedBy:    []int{},
+		Blocking:     []int{},
+		SeriesID:     task.SeriesID,
+		Occurrence:   task.Occurrence,
+		DeletedAt:    task.DeletedAt,
+		Version:      task.Version,
+	}
+	if task.Text != nil {
+		taskOut.Text = *task.Text
+	}
+	if task.Recurrence != nil {
+		taskOut.Recurrence = *task.Recurrence
+	}
+	return taskOut
+}
+
+
+const (
+	defaultTasksLimit = 50
+	maxTasksLimit     = 200
+)
+
+// taskCursor is what an opaque next_cursor decodes to. Sort and order are
+// kept in it so a cursor can't be replayed against a differently sorted list.
+type taskCursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d"`
+	Value string `json:"v"`
+	ID    int    `json:"i"`
+}
+
+func encodeCursor(c taskCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (taskCursor, error) {
+	var c taskCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, domain.ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, domain.ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// groupTasks splits tasks by status in workflow order. Tasks left in a status
+// the workflow no longer has get their own groups at the end.
+func groupTasks(wf models.Workflow, tasks []TaskOut) []TaskGroup {
+	groups := make([]TaskGroup, 0, len(wf.Statuses))
+	index := make(map[string]int, len(wf.Statuses))
+	for _, status := range wf.Statuses {
+		index[status.Name] = len(groups)
+		groups = append(groups, TaskGroup{Status: status.Name, Category: string(status.Category), Tasks: []TaskOut{}})
+	}
+
+	for _, task := range tasks {
+		i, ok := index[task.Status]
+		if !ok {
+			i = len(groups)
+			index[task.Status] = i
+			groups = append(groups, TaskGroup{Status: task.Status, Tasks: []TaskOut{}})
+		}
+		groups[i].Tasks = append(groups[i].Tasks, task)
+	}
+	return groups
+}
+
+func (s *TaskService) GetUserTasks(ctx context.Context, userID int, input TaskListInput) (TaskList, error) {
+	filter, limit, err := listFilter(input)
+	if err != nil {
+		return TaskList{}, err
+	}
+	tasks, err := s.repo.GetUserTasks(ctx, userID, filter)
+	if err != nil {
+		return TaskList{}, err
+	}
+	wf, _, err := loadWorkflow(ctx, s.workflows, userID)
+	if err != nil {
+		return TaskList{}, err
+	}
+
+	var list TaskList
+	tasks, list.NextCursor = pageTasks(filter, tasks, limit)
+	tasksOut, err := s.tasksOut(ctx, tasks)
+	if err != nil {
+		return TaskList{}, err
+	}
+	list.Groups = groupTasks(wf, tasksOut)
+	return list, nil
+}
+
+// GetTasksOfProjects lists the tasks of many projects at once, each project
+// is filtered and paged on its own the way GetUserTasks does it.
+func (s *TaskService) GetTasksOfProjects(ctx context.Context, userID int, projectIDs []int,
+	input TaskListInput) (map[int]TaskList, error) {
+	filter, limit, err := listFilter(input)
+	if err != nil {
+		return nil, err
+	}
+	filter.ProjectID = nil
+	filter.ProjectIDs = projectIDs
+	tasks, err := s.repo.GetUserTasks(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	wf, _, err := loadWorkflow(ctx, s.workflows, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byProject := make(map[int][]models.Task, len(projectIDs))
+	for _, task := range tasks {
+		byProject[*task.ProjectID] = append(byProject[*task.ProjectID], task)
+	}
+	cursors := make(map[int]string, len(byProject))
+	var page []models.Task
+	for projectID, projectTasks := range byProject {
+		projectTasks, cursors[projectID] = pageTasks(filter, projectTasks, limit)
+		page = append(page, projectTasks...)
+	}
+
+	// the relations of all projects' tasks are loaded together
+	tasksOut, err := s.tasksOut(ctx, page)
+	if err != nil {
+		return nil, err
 	}
-	return taskOut, nil
+	outByProject := make(map[int][]TaskOut, len(byProject))
+	for _, task := range tasksOut {
+		outByProject[*task.ProjectID] = append(outByProject[*task.ProjectID], task)
+	}
+
+	lists := make(map[int]TaskList, len(projectIDs))
+	for _, projectID := range projectIDs {
+		lists[projectID] = TaskList{
+			Groups:     groupTasks(wf, outByProject[projectID]),
+			NextCursor: cursors[projectID],
+		}
+	}
+	return lists, nil
 }
 
+// listFilter turns a task list request into a filter. The filter asks for
+// one task more than the returned limit, see pageTasks.
+func listFilter(input TaskListInput) (models.TaskFilter, int, error) {
+	filter := models.TaskFilter{
+		ProjectID:     input.ProjectID,
+		SeriesID:      input.SeriesID,
+		Status:        input.Status,
+		Search:        input.Search,
+		CreatedFrom:   input.CreatedFrom,
+		CreatedTo:     input.CreatedTo,
+		CompletedFrom: input.CompletedFrom,
+		CompletedTo:   input.CompletedTo,
+		SortBy:        models.TaskSortField(input.SortBy),
+	}
+	if len(input.Tags) > 0 {
+		filter.Tags = uniqueTagNames(input.Tags)
+		filter.TagMatch = models.TagMatch(input.TagMatch)
+		switch filter.TagMatch {
+		case "":
+			filter.TagMatch = models.TagMatchAll
+		case models.TagMatchAll, models.TagMatchAny:
+		default:
+			return filter, 0, domain.ErrInvalidTaskFilter
+		}
+	}
+	if input.Overdue {
+		now := time.Now().UTC()
+		filter.OverdueAt = &now
+	}
+	if filter.SortBy == "" {
+		filter.SortBy = models.TaskSortCreatedAt
+	}
 
-func (s *TaskService) GetUserTasks(ctx context.Context, userID int) (completedTasks []TaskOut, pendingTasks []TaskOut, err error) {
-    tasks, err := s.repo.GetUserTasks(ctx, userID)
-    if err != nil {
-        return nil, nil, err
-    }
-    if len(tasks) == 0 {
-        return nil, nil, domain.ErrTaskNotFound
-    }
+	switch input.Order {
+	case "", "asc":
+	case "desc":
+		filter.Desc = true
+	default:
+		return filter, 0, domain.ErrInvalidTaskFilter
+	}
 
-    for _, task := range tasks {
-        taskOut := TaskOut{
-            ID:     task.ID,
-			Title: task.Title,
-            Status: task.Status,
-        }
-        if task.Text != nil {
-            taskOut.Text = *task.Text
-        }
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultTasksLimit
+	}
+	if limit > maxTasksLimit {
+		limit = maxTasksLimit
+	}
+	// one extra row tells whether there is a next page
+	filter.Limit = limit + 1
 
-        if task.Time != nil {
-            taskOut.Time = *task.Time
-        }
+	if input.Cursor != "" {
+		c, err := decodeCursor(input.Cursor)
+		if err != nil {
+			return filter, 0, err
+		}
+		if c.Sort != string(filter.SortBy) || c.Desc != filter.Desc {
+			return filter, 0, domain.ErrInvalidCursor
+		}
+		filter.After = &models.TaskCursor{Value: c.Value, ID: c.ID}
+	}
+	return filter, limit, nil
+}
 
-        if task.Status == "completed" {
-            completedTasks = append(completedTasks, taskOut)
-        } else if task.Status == "pending" {
-            pendingTasks = append(pendingTasks, taskOut)
-        }
-    }
+// pageTasks cuts tasks down to limit and returns the cursor of the next
+// page, empty when there is none.
+func pageTasks(filter models.TaskFilter, tasks []models.Task, limit int) ([]models.Task, string) {
+	if len(tasks) <= limit {
+		return tasks, ""
+	}
+	tasks = tasks[:limit]
+	last := tasks[len(tasks)-1]
+	c := taskCursor{Sort: string(filter.SortBy), Desc: filter.Desc, ID: last.ID}
+	switch filter.SortBy {
+	case models.TaskSortCreatedAt:
+		c.Value = last.CreatedAt.Format(time.RFC3339Nano)
+	case models.TaskSortUpdatedAt:
+		c.Value = last.UpdatedAt.Format(time.RFC3339Nano)
+	case models.TaskSortDueAt:
+		c.Value = "infinity"
+		if last.DueAt != nil {
+			c.Value = last.DueAt.Format(time.RFC3339Nano)
+		}
+	case models.TaskSortPriority:
+		c.Value = strconv.Itoa(int(last.Priority))
+	case models.TaskSortTitle:
+		c.Value = last.Title
+	}
+	return tasks, encodeCursor(c)
+}
 
-    return completedTasks, pendingTasks, nil
+// utc normalizes client supplied times, the tasks table stores them without a time zone.
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// parseRecurrence validates an RRULE and returns it in canonical form, "" means no recurrence.
+func parseRecurrence(s string) (*string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	rule, err := rrule.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidRecurrence, err)
+	}
+	canonical := rule.String()
+	return &canonical, nil
+}
+
+func parsePriority(s string) (models.Priority, error) {
+	if s == "" {
+		return models.PriorityNormal, nil
+	}
+	p, ok := models.ParsePriority(s)
+	if !ok {
+		return 0, domain.ErrInvalidPriority
+	}
+	return p, nil
 }
 
 func (s *TaskService) CreateTask(ctx context.Context, userID int, input TaskInput) (int, error) {
+	priority, err := parsePriority(input.Priority)
+	if err != nil {
+		return 0, err
+	}
+	user, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if !s.accounts.mayCreateTasks(user, time.Now().UTC()) {
+		return 0, domain.ErrEmailNotVerified
+	}
+	if input.ParentID != nil {
+		parent, err := s.repo.GetTaskByID(ctx, userID, *input.ParentID)
+		if err != nil {
+			return 0, err
+		}
+		ancestors, err := s.repo.GetAncestors(ctx, parent.ID)
+		if err != nil {
+			return 0, err
+		}
+		if len(ancestors)+2 > maxTaskDepth {
+			return 0, domain.ErrSubtaskTooDeep
+		}
+		// subtasks live in the project of their parent unless told otherwise
+		if input.ProjectID == nil {
+			input.ProjectID = parent.ProjectID
+		}
+	}
+	if input.ProjectID != nil {
+		if _, err := s.projects.GetProjectByID(ctx, userID, *input.ProjectID); err != nil {
+			return 0, err
+		}
+	}
+	wf, _, err := loadWorkflow(ctx, s.workflows, userID)
+	if err != nil {
+		return 0, err
+	}
 	task := models.Task{
-		UserID: userID,
-		Title:  input.Title,
-		Status: "pending",
+		UserID:    userID,
+		ProjectID: input.ProjectID,
+		ParentID:  input.ParentID,
+		Title:     input.Title,
+		Status:    wf.InitialStatus(),
+		Priority:  priority,
+		DueAt:     utc(input.DueAt),
+		CreatedAt: time.Now().UTC(),
+	}
+	if input.Text != "" {
+		task.Text = &input.Text
+	}
+	if input.AutoComplete != nil {
+		task.AutoComplete = *input.AutoComplete
+	}
+	if input.Recurrence != nil {
+		task.Recurrence, err = parseRecurrence(*input.Recurrence)
+		if err != nil {
+			return 0, err
+		}
 	}
 	taskID, err := s.repo.CreateTask(ctx, userID, task)
 	if err != nil {
@@ -81,38 +459,566 @@ func (s *TaskService) CreateTask(ctx context.Context, userID int, input TaskInpu
 	return taskID, nil
 }
 
-func (s *TaskService) UpdateTask(ctx context.Context, taskID int, input TaskInput) error {
-    var currentTime *time.Time
+// maxWriteAttempts bounds how often a change made without a version is
+// checked and written again after the task changed meanwhile.
+const maxWriteAttempts = 3
+
+// retryOnConflict runs write, which checks a change against the task it reads
+// and saves it only while the task is still at the version read. A change
+// without a version is tried again when the task changed in between, one
+// made at a version fails then.
+func retryOnConflict(version int, write func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := write()
+		if version != 0 || attempt == maxWriteAttempts || !errors.Is(err, domain.ErrTaskVersionMismatch) {
+			return err
+		}
+	}
+}
+
+func (s *TaskService) UpdateTask(ctx context.Context, userID, taskID int, input TaskInput) error {
+	var (
+		task      *models.Task
+		completed bool
+	)
+	err := retryOnConflict(input.Version, func() (err error) {
+		task, completed, err = s.updateTask(ctx, userID, taskID, input)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if completed {
+		// the change is saved already, a failed follow-up mustn't undo that
+		if err := s.afterCompletion(ctx, userID, *task); err != nil {
+			s.log.Error(fmt.Errorf("TaskService - UpdateTask - afterCompletion: %w", err))
+		}
+	}
+	return nil
+}
 
-    if input.Status == "pending" {
-        currentTime = nil
-    } else {
-        now := time.Now()
-        currentTime = &now
-    }
+// updateTask makes one attempt of UpdateTask and reports whether it
+// completed the task.
+func (s *TaskService) updateTask(ctx context.Context, userID, taskID int, input TaskInput) (*models.Task, bool, error) {
+	task, err := s.repo.GetTaskByID(ctx, userID, taskID)
+	if err != nil {
+		return nil, false, err
+	}
+	if input.Version != 0 && task.Version != input.Version {
+		return nil, false, domain.ErrTaskVersionMismatch
+	}
 
-    task := models.Task{
-        Title:  input.Title,
-        Status: input.Status,
-        Text:   &input.Text,
-        Time:   currentTime, 
-    }
+	if input.Priority != "" {
+		task.Priority, err = parsePriority(input.Priority)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	now := time.Now().UTC()
+	completed := false
+	if input.Status != "" {
+		completed, err = s.changeStatus(ctx, userID, task, input.Status, input.Force, now)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	task.Title = input.Title
+	task.Text = &input.Text
+	task.DueAt = utc(input.DueAt)
+	if input.AutoComplete != nil {
+		task.AutoComplete = *input.AutoComplete
+	}
+	if input.Recurrence != nil {
+		task.Recurrence, err = parseRecurrence(*input.Recurrence)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	task.UpdatedAt = now
 
-    err := s.repo.UpdateTask(ctx, taskID, task)
-    if err != nil {
-        return err
-    }
-    return nil
+	// written only while the task is at the version read, so the status
+	// change was checked against the task as it is
+	if err := s.repo.UpdateTask(ctx, userID, taskID, *task); err != nil {
+		return nil, false, err
+	}
+	return task, completed, nil
 }
 
+// PatchTask changes only the fields set in the input.
+func (s *TaskService) PatchTask(ctx context.Context, userID, taskID int, input TaskPatchInput) error {
+	var (
+		task      models.Task
+		completed bool
+	)
+	err := retryOnConflict(input.Version, func() (err error) {
+		task, completed, err = s.patchTask(ctx, userID, taskID, input)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if completed {
+		// the change is saved already, a failed follow-up mustn't undo that
+		if err := s.afterCompletion(ctx, userID, task); err != nil {
+			s.log.Error(fmt.Errorf("TaskService - PatchTask - afterCompletion: %w", err))
+		}
+	}
+	return nil
+}
 
+// patchTask makes one attempt of PatchTask, it returns the task as patched
+// and reports whether the patch completed it.
+func (s *TaskService) patchTask(ctx context.Context, userID, taskID int, input TaskPatchInput) (models.Task, bool, error) {
+	task, err := s.repo.GetTaskByID(ctx, userID, taskID)
+	if err != nil {
+		return models.Task{}, false, err
+	}
+	if input.Version != 0 && task.Version != input.Version {
+		return models.Task{}, false, domain.ErrTaskVersionMismatch
+	}
 
+	now := time.Now().UTC()
+	patch := models.TaskPatch{
+		Title:        input.Title,
+		Text:         input.Text,
+		AutoComplete: input.AutoComplete,
+		UpdatedAt:    now,
+		// written only while the task is at the version read, so the
+		// status change was checked against the task as it is
+		Version: task.Version,
+	}
+	completed := false
+	if input.Status.Set {
+		completed, err = s.changeStatus(ctx, userID, task, *input.Status.Value, input.Force, now)
+		if err != nil {
+			return models.Task{}, false, err
+		}
+		patch.Status = models.Update(task.Status)
+		patch.CompletedAt = models.Nullable[time.Time]{Set: true, Value: task.CompletedAt}
+	}
+	if input.Priority.Set {
+		priority, err := parsePriority(*input.Priority.Value)
+		if err != nil {
+			return models.Task{}, false, err
+		}
+		patch.Priority = models.Update(priority)
+	}
+	if input.DueAt.Set {
+		patch.DueAt = models.Nullable[time.Time]{Set: true, Value: utc(input.DueAt.Value)}
+	}
+	if input.Recurrence.Set {
+		patch.Recurrence.Set = true
+		if input.Recurrence.Value != nil {
+			patch.Recurrence.Value, err = parseRecurrence(*input.Recurrence.Value)
+			if err != nil {
+				return models.Task{}, false, err
+			}
+		}
+	}
+	if input.ProjectID.Set {
+		if input.ProjectID.Value != nil {
+			if _, err := s.projects.GetProjectByID(ctx, userID, *input.ProjectID.Value); err != nil {
+				return models.Task{}, false, err
+			}
+		}
+		patch.ProjectID = input.ProjectID
+	}
 
+	if err := s.repo.PatchTask(ctx, userID, taskID, patch); err != nil {
+		return models.Task{}, false, err
+	}
+	return patch.Apply(*task), completed, nil
+}
 
-func (s *TaskService) DeleteTask(ctx context.Context, taskID int) error {
-	err := s.repo.DeleteTask(ctx, taskID)
+// changeStatus checks that the task may move to status and moves it, setting
+// completed_at by the category of the new status. It reports whether the
+// change completed the task.
+func (s *TaskService) changeStatus(ctx context.Context, userID int, task *models.Task, status string, force bool,
+	now time.Time) (bool, error) {
+	if status == task.Status {
+		return false, nil
+	}
+	wf, _, err := loadWorkflow(ctx, s.workflows, userID)
+	if err != nil {
+		return false, err
+	}
+	next, ok := wf.Status(status)
+	if !ok {
+		return false, domain.ErrInvalidStatus
+	}
+	if !wf.CanTransition(task.Status, status) {
+		return false, domain.ErrStatusTransition
+	}
+
+	wasDone := task.CompletedAt != nil
+	done := next.Category == models.CategoryDone
+	if done && !wasDone && !force {
+		blockers, err := s.dependencies.GetOpenBlockers(ctx, task.ID)
+		if err != nil {
+			return false, err
+		}
+		if len(blockers) > 0 {
+			return false, domain.ErrTaskBlocked
+		}
+	}
+
+	task.Status = status
+	if !done {
+		task.CompletedAt = nil
+	} else if !wasDone {
+		task.CompletedAt = &now
+	}
+	return done && !wasDone, nil
+}
+
+// afterCompletion starts the next occurrence of a recurring task and lets
+// the parent complete itself. It runs once the completion is saved, so it
+// goes on when the client goes away and a failed step doesn't stop the
+// others. Callers log the error rather than fail, a retry of the request
+// would find the task completed and not get here again.
+func (s *TaskService) afterCompletion(ctx context.Context, userID int, task models.Task) error {
+	ctx = context.WithoutCancel(ctx)
+	var errs []error
+	if task.Recurrence != nil {
+		if err := s.createNextOccurrence(ctx, userID, task); err != nil {
+			errs = append(errs, fmt.Errorf("next occurrence of task %d: %w", task.ID, err))
+		}
+	}
+	if task.ParentID != nil {
+		if err := s.autoCompleteParent(ctx, userID, *task.ParentID); err != nil {
+			errs = append(errs, fmt.Errorf("parent of task %d: %w", task.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// createNextOccurrence adds the task that follows a completed recurring one.
+// Occurrences that are already in the past are skipped.
+func (s *TaskService) createNextOccurrence(ctx context.Context, userID int, task models.Task) error {
+	rule, err := rrule.Parse(*task.Recurrence)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidRecurrence, err)
+	}
+
+	now := time.Now().UTC()
+	prev := *task.CompletedAt
+	if task.DueAt != nil {
+		prev = *task.DueAt
+	}
+	occurrence := task.Occurrence
+	next, ok := rule.Next(prev, occurrence)
+	for ok && next.Before(now) {
+		occurrence++
+		next, ok = rule.Next(next, occurrence)
+	}
+	if !ok {
+		return nil
+	}
+
+	wf, _, err := loadWorkflow(ctx, s.workflows, userID)
+	if err != nil {
+		return err
+	}
+	seriesID := task.ID
+	if task.SeriesID != nil {
+		seriesID = *task.SeriesID
+	}
+	nextTask := models.Task{
+		UserID:       userID,
+		ProjectID:    task.ProjectID,
+		ParentID:     task.ParentID,
+		Title:        task.Title,
+		Text:         task.Text,
+		Status:       wf.InitialStatus(),
+		Priority:     task.Priority,
+		DueAt:        &next,
+		AutoComplete: task.AutoComplete,
+		Recurrence:   task.Recurrence,
+		SeriesID:     &seriesID,
+		Occurrence:   occurrence + 1,
+		CreatedAt:    now,
+	}
+	nextID, err := s.repo.CreateTask(ctx, userID, nextTask)
+	if err != nil {
+		// completed before, the next occurrence is already there
+		if errors.Is(err, domain.ErrOccurrenceExists) {
+			return nil
+		}
+		return err
+	}
+
+	tags, err := s.tags.GetTasksTags(ctx, []int{task.ID})
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, tag := range tags[task.ID] {
+		if err := s.tags.AttachTag(ctx, nextID, tag.ID); err != nil {
+			errs = append(errs, fmt.Errorf("tag %d: %w", tag.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// autoCompleteParent moves a parent that asked for it to a done status once
+// all its subtasks are done, and goes on up the tree. A recurring parent
+// gets its next occurrence like one completed by hand.
+func (s *TaskService) autoCompleteParent(ctx context.Context, userID, parentID int) error {
+	wf, _, err := loadWorkflow(ctx, s.workflows, userID)
+	if err != nil {
+		return err
+	}
+
+	conflicts := 0
+	for {
+		parent, err := s.repo.GetTaskByID(ctx, userID, parentID)
+		if err != nil {
+			return err
+		}
+		if !parent.AutoComplete || parent.CompletedAt != nil {
+			return nil
+		}
+
+		progress, err := s.repo.GetSubtaskProgress(ctx, []int{parent.ID})
+		if err != nil {
+			return err
+		}
+		if p := progress[parent.ID]; p.Done < p.Total {
+			return nil
+		}
+		blockers, err := s.dependencies.GetOpenBlockers(ctx, parent.ID)
+		if err != nil {
+			return err
+		}
+		if len(blockers) > 0 {
+			return nil
+		}
+
+		status := ""
+		for _, st := range wf.Statuses {
+			if st.Category == models.CategoryDone && wf.CanTransition(parent.Status, st.Name) {
+				status = st.Name
+				break
+			}
+		}
+		if status == "" {
+			return nil
+		}
+
+		now := time.Now().UTC()
+		parent.Status = status
+		parent.CompletedAt = &now
+		parent.UpdatedAt = now
+		err = s.repo.UpdateTask(ctx, userID, parent.ID, *parent)
+		if errors.Is(err, domain.ErrTaskVersionMismatch) && conflicts < maxWriteAttempts {
+			// the parent changed since it was checked, check it again
+			conflicts++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if parent.Recurrence != nil {
+			if err := s.createNextOccurrence(ctx, userID, *parent); err != nil {
+				// the parent is completed, its own parent may still follow
+				s.log.Error(fmt.Errorf("TaskService - autoCompleteParent - createNextOccurrence of task %d: %w", parent.ID, err))
+			}
+		}
+
+		if parent.ParentID == nil {
+			return nil
+		}
+		parentID = *parent.ParentID
+	}
+}
+
+func (s *TaskService) SetParent(ctx context.Context, userID, taskID int, parentID *int, version int) error {
+	if parentID == nil {
+		return s.repo.SetParent(ctx, userID, taskID, nil, version)
+	}
+	if *parentID == taskID {
+		return domain.ErrSubtaskCycle
+	}
+
+	if _, err := s.repo.GetTaskByID(ctx, userID, taskID); err != nil {
+		return err
+	}
+	if _, err := s.repo.GetTaskByID(ctx, userID, *parentID); err != nil {
+		return err
+	}
+
+	ancestors, err := s.repo.GetAncestors(ctx, *parentID)
+	if err != nil {
+		return err
+	}
+	for _, id := range ancestors {
+		if id == taskID {
+			return domain.ErrSubtaskCycle
+		}
+	}
+
+	depth, err := s.repo.GetSubtreeDepth(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if len(ancestors)+2+depth > maxTaskDepth {
+		return domain.ErrSubtaskTooDeep
+	}
+
+	return s.repo.SetParent(ctx, userID, taskID, parentID, version)
+}
+
+func (s *TaskService) AddDependency(ctx context.Context, userID, taskID, blockedByID int) error {
+	if taskID == blockedByID {
+		return domain.ErrDependencyCycle
+	}
+	if _, err := s.repo.GetTaskByID(ctx, userID, taskID); err != nil {
+		return err
+	}
+	if _, err := s.repo.GetTaskByID(ctx, userID, blockedByID); err != nil {
+		return err
+	}
+
+	// the new edge closes a cycle if the task is already among
+	// the blockers of its blocker, however indirectly
+	visited := map[int]bool{blockedByID: true}
+	frontier := []int{blockedByID}
+	for len(frontier) > 0 {
+		blockers, err := s.dependencies.GetBlockers(ctx, frontier)
+		if err != nil {
+			return err
+		}
+		frontier = frontier[:0]
+		for _, ids := range blockers {
+			for _, id := range ids {
+				if id == taskID {
+					return domain.ErrDependencyCycle
+				}
+				if !visited[id] {
+					visited[id] = true
+					frontier = append(frontier, id)
+				}
+			}
+		}
+	}
+
+	return s.dependencies.AddDependency(ctx, taskID, blockedByID)
+}
+
+func (s *TaskService) RemoveDependency(ctx context.Context, userID, taskID, blockedByID int) error {
+	if _, err := s.repo.GetTaskByID(ctx, userID, taskID); err != nil {
+		return err
+	}
+	return s.dependencies.RemoveDependency(ctx, taskID, blockedByID)
+}
+
+func (s *TaskService) GetSubtasks(ctx context.Context, userID, taskID int) ([]TaskOut, error) {
+	if _, err := s.repo.GetTaskByID(ctx, userID, taskID); err != nil {
+		return nil, err
+	}
+	tasks, err := s.repo.GetSubtasks(ctx, userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return s.tasksOut(ctx, tasks)
+}
+
+func (s *TaskService) GetTasksByIDs(ctx context.Context, userID int, taskIDs []int) ([]TaskOut, error) {
+	tasks, err := s.repo.GetTasksByIDs(ctx, userID, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	return s.tasksOut(ctx, tasks)
+}
+
+func (s *TaskService) GetSubtasksOf(ctx context.Context, userID int, parentIDs []int) ([]TaskOut, error) {
+	tasks, err := s.repo.GetSubtasksOf(ctx, userID, parentIDs)
+	if err != nil {
+		return nil, err
+	}
+	return s.tasksOut(ctx, tasks)
+}
+
+func (s *TaskService) MoveTask(ctx context.Context, userID, taskID int, projectID *int, version int) error {
+	if projectID != nil {
+		if _, err := s.projects.GetProjectByID(ctx, userID, *projectID); err != nil {
+			return err
+		}
+	}
+	return s.repo.MoveTask(ctx, userID, taskID, projectID, version)
+}
+
+func (s *TaskService) DeleteTask(ctx context.Context, userID, taskID, version int) error {
+	err := s.repo.DeleteTask(ctx, userID, taskID, version)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+func (s *TaskService) GetTaskHistory(ctx context.Context, userID, taskID int) ([]TaskEventOut, error) {
+	if _, err := s.repo.GetTaskByID(ctx, userID, taskID); err != nil {
+		return nil, err
+	}
+	events, err := s.repo.GetTaskEvents(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return taskEventsOut(events), nil
+}
+
+const (
+	// watchInterval is how often WatchTasks looks for new events.
+	watchInterval  = time.Second
+	watchBatchSize = 100
+)
+
+func (s *TaskService) WatchTasks(ctx context.Context, userID int, afterID int64, send func([]TaskEventOut) error) error {
+	cursor, err := s.repo.GetTaskEventCursor(ctx, userID, afterID)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		events, next, err := s.repo.GetUserTaskEvents(ctx, userID, cursor, watchBatchSize)
+		if err != nil {
+			return err
+		}
+		cursor = next
+		if len(events) > 0 {
+			if err := send(taskEventsOut(events)); err != nil {
+				return err
+			}
+			// a full batch means more events are waiting
+			if len(events) == watchBatchSize {
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func taskEventsOut(events []models.TaskEvent) []TaskEventOut {
+	eventsOut := make([]TaskEventOut, 0, len(events))
+	for _, e := range events {
+		eventsOut = append(eventsOut, TaskEventOut{
+			ID:        e.ID,
+			TaskID:    e.TaskID,
+			UserID:    e.UserID,
+			Action:    string(e.Action),
+			Field:     e.Field,
+			OldValue:  e.OldValue,
+			NewValue:  e.NewValue,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+	return eventsOut
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yosakoo/task-traker/internal/repository"
+	"github.com/yosakoo/task-traker/pkg/logger"
+)
+
+type TrashService struct {
+	repo  repo.Tasks
+	tasks *TaskService
+	blobs BlobStore
+	log   *logger.Logger
+}
+
+func NewTrashService(repo repo.Tasks, tasks *TaskService, blobs BlobStore, log *logger.Logger) *TrashService {
+	return &TrashService{
+		repo:  repo,
+		tasks: tasks,
+		blobs: blobs,
+		log:   log,
+	}
+}
+
+func (s *TrashService) GetTrash(ctx context.Context, userID int) ([]TaskOut, error) {
+	tasks, err := s.repo.GetTrash(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.tasks.tasksOut(ctx, tasks)
+}
+
+func (s *TrashService) RestoreTask(ctx context.Context, userID, taskID int) error {
+	return s.repo.RestoreTask(ctx, userID, taskID)
+}
+
+func (s *TrashService) DeleteTask(ctx context.Context, userID, taskID int) error {
+	keys, err := s.repo.PurgeTask(ctx, userID, taskID)
+	if err != nil {
+		return err
+	}
+	s.deleteBlobs(ctx, keys)
+	return nil
+}
+
+func (s *TrashService) Purge(ctx context.Context, retention time.Duration) error {
+	keys, err := s.repo.PurgeTrash(ctx, time.Now().UTC().Add(-retention))
+	if err != nil {
+		return err
+	}
+	s.deleteBlobs(ctx, keys)
+	return nil
+}
+
+// deleteBlobs removes attachments of purged tasks. The tasks are gone
+// already, so a blob left behind is only logged.
+func (s *TrashService) deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			s.log.Error(fmt.Errorf("TrashService - deleteBlobs - blobs.Delete: %w", err))
+		}
+	}
+}
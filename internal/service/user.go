@@ -2,8 +2,12 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"net/url"
 	"strconv"
 	"time"
 
@@ -24,10 +28,11 @@ type UsersService struct {
 
 	accessTokenTTL  time.Duration
 	refreshTokenTTL time.Duration
+	accounts        AccountOptions
 }
 
 func NewUserService(repo repo.Users, log *logger.Logger, hasher hash.PasswordHasher, tokenManager auth.TokenManager,
-	emailService Emails, accessTTL time.Duration, refreshTTL time.Duration) *UsersService {
+	emailService Emails, accessTTL time.Duration, refreshTTL time.Duration, accounts AccountOptions) *UsersService {
 	return &UsersService{
 		repo:            repo,
 		log:             log,
@@ -36,6 +41,7 @@ func NewUserService(repo repo.Users, log *logger.Logger, hasher hash.PasswordHas
 		emailService:    emailService,
 		accessTokenTTL:  accessTTL,
 		refreshTokenTTL: refreshTTL,
+		accounts:        accounts,
 	}
 }
 
@@ -46,9 +52,10 @@ func (s *UsersService) SignUp(ctx context.Context, input UserSignUpInput) (Token
 	}
 
 	user := models.User{
-		Name:     input.Name,
-		Password: passwordHash,
-		Email:    input.Email,
+		Name:      input.Name,
+		Password:  passwordHash,
+		Email:     input.Email,
+		CreatedAt: time.Now().UTC(),
 	}
 	userId, err := s.repo.AddUser(ctx, user)
 	if err != nil {
@@ -58,12 +65,10 @@ func (s *UsersService) SignUp(ctx context.Context, input UserSignUpInput) (Token
 		}
 		return Tokens{}, err
 	}
-	email := &Email{
-		Subject: "Регистрация",
-		Body:    "Добро пожаловать в Task Traker!",
-		To:      user.Email,
-	}
-	if err := s.emailService.SendEmail(ctx, email); err != nil {
+	user.ID = userId
+	// the account works without a verified email, a failed email can be
+	// sent again later
+	if err := s.sendVerification(ctx, &user, "Регистрация", "Добро пожаловать в Task Traker!", time.Time{}); err != nil {
 		s.log.Error(err)
 	}
 	return s.createSession(ctx, userId)
@@ -92,7 +97,6 @@ func (s *UsersService) SignIn(ctx context.Context, input UserSignInInput) (Token
 }
 
 func (s *UsersService) RefreshTokens(ctx context.Context, refreshToken string) (Tokens, error) {
-	fmt.Println(refreshToken)
 	userId, err := s.repo.GetUserByRefresh(ctx, refreshToken)
 	if err != nil {
 		return Tokens{}, err
@@ -133,10 +137,134 @@ func (s *UsersService) GetUserByID(ctx context.Context, userID int) (AuthUser, e
 		return AuthUser{}, err
 	}
 	authUser := AuthUser{
-		ID:    user.ID,
-		Name:  user.Name,
-		Email: user.Email,
+		ID:         user.ID,
+		Name:       user.Name,
+		Email:      user.Email,
+		VerifiedAt: user.VerifiedAt,
 	}
 
 	return authUser, nil
 }
+
+func (s *UsersService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		// the client isn't told whether the email belongs to a user
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, tokenHash, err := newSecretToken()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	expiresAt := now.Add(s.accounts.PasswordResetTTL)
+	err = s.repo.CreatePasswordReset(ctx, user.ID, tokenHash, now, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	link, err := withToken(s.accounts.PasswordResetURL, token)
+	if err != nil {
+		return err
+	}
+	resetEmail := &Email{
+		Subject: "Сброс пароля",
+		Body: fmt.Sprintf("Чтобы задать новый пароль, перейдите по ссылке: %s\n"+
+			"Ссылка действует до %s UTC. Если вы не запрашивали сброс пароля, проигнорируйте это письмо.",
+			link, expiresAt.Format("02.01.2006 15:04")),
+		To: user.Email,
+	}
+	if err := s.emailService.SendEmail(ctx, resetEmail); err != nil {
+		s.log.Error(err)
+	}
+	return nil
+}
+
+func (s *UsersService) ResetPassword(ctx context.Context, token, password string) error {
+	passwordHash, err := s.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+	_, err = s.repo.ResetPassword(ctx, hashToken(token), passwordHash, time.Now().UTC())
+	return err
+}
+
+func (s *UsersService) VerifyEmail(ctx context.Context, token string) error {
+	_, err := s.repo.VerifyEmail(ctx, hashToken(token), time.Now().UTC())
+	return err
+}
+
+func (s *UsersService) ResendVerification(ctx context.Context, userID int) error {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.VerifiedAt != nil {
+		return domain.ErrEmailAlreadyVerified
+	}
+	notBefore := time.Now().UTC().Add(-s.accounts.VerificationResendInterval)
+	return s.sendVerification(ctx, user, "Подтверждение адреса", "", notBefore)
+}
+
+// sendVerification emails the user a new verification link, unless another
+// one was created after notBefore.
+func (s *UsersService) sendVerification(ctx context.Context, user *models.User, subject, greeting string, notBefore time.Time) error {
+	token, tokenHash, err := newSecretToken()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	expiresAt := now.Add(s.accounts.VerificationTTL)
+	err = s.repo.CreateEmailVerification(ctx, user.ID, tokenHash, now, expiresAt, notBefore)
+	if err != nil {
+		return err
+	}
+
+	link, err := withToken(s.accounts.VerificationURL, token)
+	if err != nil {
+		return err
+	}
+	body := fmt.Sprintf("Чтобы подтвердить адрес электронной почты, перейдите по ссылке: %s\n"+
+		"Ссылка действует до %s UTC.", link, expiresAt.Format("02.01.2006 15:04"))
+	if greeting != "" {
+		body = greeting + "\n" + body
+	}
+	email := &Email{
+		Subject: subject,
+		Body:    body,
+		To:      user.Email,
+	}
+	return s.emailService.SendEmail(ctx, email)
+}
+
+// newSecretToken returns a random token to send to the user and the hash of
+// it to store.
+func newSecretToken() (token, tokenHash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = hex.EncodeToString(b)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// withToken adds token to the query of the page at rawURL.
+func withToken(rawURL, token string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yosakoo/task-traker/internal/domain"
+	"github.com/yosakoo/task-traker/internal/domain/models"
+	"github.com/yosakoo/task-traker/internal/repository"
+)
+
+const maxStatusNameLength = 20
+
+type WorkflowService struct {
+	repo repo.Workflows
+}
+
+func NewWorkflowService(repo repo.Workflows) *WorkflowService {
+	return &WorkflowService{
+		repo: repo,
+	}
+}
+
+// loadWorkflow returns the user's workflow or the default one if they have none.
+func loadWorkflow(ctx context.Context, workflows repo.Workflows, userID int) (models.Workflow, bool, error) {
+	wf, err := workflows.GetWorkflow(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrWorkflowNotFound) {
+			return models.DefaultWorkflow(), true, nil
+		}
+		return models.Workflow{}, false, err
+	}
+	return *wf, false, nil
+}
+
+func (s *WorkflowService) GetWorkflow(ctx context.Context, userID int) (WorkflowOut, error) {
+	wf, isDefault, err := loadWorkflow(ctx, s.repo, userID)
+	if err != nil {
+		return WorkflowOut{}, err
+	}
+
+	out := WorkflowOut{
+		Statuses:    make([]WorkflowStatus, 0, len(wf.Statuses)),
+		Transitions: make([]WorkflowTransition, 0, len(wf.Transitions)),
+		IsDefault:   isDefault,
+	}
+	for _, status := range wf.Statuses {
+		out.Statuses = append(out.Statuses, WorkflowStatus{Name: status.Name, Category: string(status.Category)})
+	}
+	for _, t := range wf.Transitions {
+		out.Transitions = append(out.Transitions, WorkflowTransition{From: t.From, To: t.To})
+	}
+	return out, nil
+}
+
+func (s *WorkflowService) SetWorkflow(ctx context.Context, userID int, input WorkflowInput) error {
+	var wf models.Workflow
+
+	seen := make(map[string]bool, len(input.Statuses))
+	for _, status := range input.Statuses {
+		category := models.StatusCategory(status.Category)
+		if status.Name == "" || len(status.Name) > maxStatusNameLength || seen[status.Name] || !category.Valid() {
+			return domain.ErrInvalidWorkflow
+		}
+		seen[status.Name] = true
+		wf.Statuses = append(wf.Statuses, models.WorkflowStatus{Name: status.Name, Category: category})
+	}
+	if wf.InitialStatus() == "" {
+		return domain.ErrInvalidWorkflow
+	}
+
+	allowed := make(map[WorkflowTransition]bool, len(input.Transitions))
+	for _, t := range input.Transitions {
+		if !seen[t.From] || !seen[t.To] || t.From == t.To {
+			return domain.ErrInvalidWorkflow
+		}
+		if allowed[t] {
+			continue
+		}
+		allowed[t] = true
+		wf.Transitions = append(wf.Transitions, models.WorkflowTransition{From: t.From, To: t.To})
+	}
+
+	return s.repo.SetWorkflow(ctx, userID, wf)
+}
@@ -0,0 +1,15 @@
+-- task_id has no foreign key so the history outlives the task
+CREATE TABLE task_events (
+    id BIGSERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    field VARCHAR(32),
+    old_value TEXT,
+    new_value TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX task_events_task_id_idx ON task_events (task_id, created_at, id);
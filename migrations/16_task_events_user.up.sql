@@ -0,0 +1 @@
+CREATE INDEX task_events_user_id_idx ON task_events (user_id, id);
@@ -0,0 +1,6 @@
+-- keys are only kept for signed in users now, the responses stored for
+-- anonymous requests carried access and refresh tokens
+DELETE FROM idempotency_keys WHERE user_id = 0;
+
+ALTER TABLE idempotency_keys
+    ADD FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
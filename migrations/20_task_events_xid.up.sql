@@ -0,0 +1,7 @@
+-- event IDs are drawn when an event is written, not when it commits, so a
+-- reader going by ID alone passes over events of slower transactions. xid
+-- is the transaction that wrote the event; readers only take events of
+-- transactions that are all finished and page by (xid, id) instead.
+ALTER TABLE task_events ADD COLUMN xid xid8 NOT NULL DEFAULT pg_current_xact_id();
+
+CREATE INDEX task_events_user_xid_idx ON task_events (user_id, xid, id);
@@ -0,0 +1,11 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE tasks ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT now();
+
+CREATE INDEX tasks_user_id_created_at_idx ON tasks (user_id, created_at, id);
+CREATE INDEX tasks_user_id_title_idx ON tasks (user_id, title, id);
+CREATE INDEX tasks_user_id_status_idx ON tasks (user_id, status);
+CREATE INDEX tasks_user_id_time_idx ON tasks (user_id, time);
+
+CREATE INDEX tasks_title_trgm_idx ON tasks USING GIN (title gin_trgm_ops);
+CREATE INDEX tasks_text_trgm_idx ON tasks USING GIN (text gin_trgm_ops);
@@ -0,0 +1,16 @@
+ALTER TABLE tasks RENAME COLUMN time TO completed_at;
+ALTER INDEX tasks_user_id_time_idx RENAME TO tasks_user_id_completed_at_idx;
+
+-- time used to be set for every status other than pending
+UPDATE tasks SET completed_at = NULL WHERE status <> 'completed';
+
+ALTER TABLE tasks
+    ADD COLUMN due_at TIMESTAMP,
+    ADD COLUMN priority SMALLINT NOT NULL DEFAULT 1 CHECK (priority BETWEEN 0 AND 3),
+    ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT now();
+
+UPDATE tasks SET updated_at = COALESCE(completed_at, created_at);
+
+CREATE INDEX tasks_user_id_due_at_idx ON tasks (user_id, due_at) WHERE completed_at IS NULL;
+CREATE INDEX tasks_user_id_priority_idx ON tasks (user_id, priority, id);
+CREATE INDEX tasks_user_id_updated_at_idx ON tasks (user_id, updated_at, id);
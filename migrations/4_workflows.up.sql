@@ -0,0 +1,20 @@
+CREATE TABLE workflow_statuses (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    name VARCHAR(20) NOT NULL,
+    category VARCHAR(20) NOT NULL CHECK (category IN ('todo', 'in_progress', 'done')),
+    position INTEGER NOT NULL,
+    UNIQUE (user_id, name),
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE workflow_transitions (
+    user_id INTEGER NOT NULL,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    PRIMARY KEY (user_id, from_status, to_status),
+
+    FOREIGN KEY (user_id, from_status) REFERENCES workflow_statuses(user_id, name) ON DELETE CASCADE,
+    FOREIGN KEY (user_id, to_status) REFERENCES workflow_statuses(user_id, name) ON DELETE CASCADE
+);
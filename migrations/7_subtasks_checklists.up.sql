@@ -0,0 +1,18 @@
+ALTER TABLE tasks
+    ADD COLUMN parent_id INTEGER REFERENCES tasks(id) ON DELETE CASCADE,
+    ADD COLUMN auto_complete BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX tasks_parent_id_idx ON tasks (parent_id);
+
+CREATE TABLE checklist_items (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL,
+    text TEXT NOT NULL,
+    done BOOLEAN NOT NULL DEFAULT false,
+    position INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX checklist_items_task_id_idx ON checklist_items (task_id, position);
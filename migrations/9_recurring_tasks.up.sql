@@ -0,0 +1,7 @@
+ALTER TABLE tasks
+    ADD COLUMN recurrence TEXT,
+    ADD COLUMN series_id INTEGER REFERENCES tasks(id) ON DELETE SET NULL,
+    ADD COLUMN occurrence INTEGER NOT NULL DEFAULT 1;
+
+-- completing the same occurrence twice must not spawn the next one twice
+CREATE UNIQUE INDEX tasks_series_id_occurrence_idx ON tasks (series_id, occurrence);
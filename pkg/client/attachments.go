@@ -0,0 +1,81 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Attachment struct {
+	ID          int       `json:"id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func attachmentPath(taskID, attachmentID int) string {
+	return taskPath(taskID) + "/attachments/" + strconv.Itoa(attachmentID)
+}
+
+func (c *Client) GetAttachments(ctx context.Context, taskID int) ([]Attachment, error) {
+	var attachments []Attachment
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: taskPath(taskID) + "/attachments"}, &attachments); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// UploadAttachment attaches the contents of r to the task. The contents are
+// read into memory first, so the upload can be retried.
+func (c *Client) UploadAttachment(ctx context.Context, taskID int, filename string, r io.Reader) (*Attachment, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return nil, err
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	req := request{
+		method: http.MethodPost,
+		path:   taskPath(taskID) + "/attachments",
+		header: http.Header{"Content-Type": {form.FormDataContentType()}},
+		body:   body.Bytes(),
+	}
+	var attachment Attachment
+	if _, err := c.do(ctx, req, &attachment); err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// DownloadAttachment returns the contents of an attachment and its content
+// type, the caller closes the contents.
+func (c *Client) DownloadAttachment(ctx context.Context, taskID, attachmentID int) (io.ReadCloser, string, error) {
+	req := request{
+		method: http.MethodGet,
+		path:   attachmentPath(taskID, attachmentID),
+		header: http.Header{"Accept": {"*/*"}},
+	}
+	var body io.ReadCloser
+	resp, err := c.do(ctx, req, &body)
+	if err != nil {
+		return nil, "", err
+	}
+	return body, resp.Header.Get("Content-Type"), nil
+}
+
+func (c *Client) DeleteAttachment(ctx context.Context, taskID, attachmentID int) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: attachmentPath(taskID, attachmentID)}, nil)
+	return err
+}
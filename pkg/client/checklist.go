@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type ChecklistItem struct {
+	ID        int       `json:"id"`
+	Text      string    `json:"text"`
+	Done      bool      `json:"done"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ChecklistItemInput struct {
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+// ChecklistItemUpdate changes the fields that are set.
+type ChecklistItemUpdate struct {
+	Text     *string `json:"text,omitempty"`
+	Done     *bool   `json:"done,omitempty"`
+	Position *int    `json:"position,omitempty"`
+}
+
+func checklistItemPath(taskID, itemID int) string {
+	return taskPath(taskID) + "/checklist/" + strconv.Itoa(itemID)
+}
+
+func (c *Client) GetChecklist(ctx context.Context, taskID int) ([]ChecklistItem, error) {
+	var items []ChecklistItem
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: taskPath(taskID) + "/checklist"}, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// AddChecklistItem adds an item to the end of the checklist and returns its ID.
+func (c *Client) AddChecklistItem(ctx context.Context, taskID int, input ChecklistItemInput) (int, error) {
+	return c.create(ctx, taskPath(taskID)+"/checklist", input)
+}
+
+func (c *Client) UpdateChecklistItem(ctx context.Context, taskID, itemID int, update ChecklistItemUpdate) error {
+	_, err := c.do(ctx, request{method: http.MethodPut, path: checklistItemPath(taskID, itemID), body: update}, nil)
+	return err
+}
+
+func (c *Client) DeleteChecklistItem(ctx context.Context, taskID, itemID int) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: checklistItemPath(taskID, itemID)}, nil)
+	return err
+}
@@ -0,0 +1,243 @@
+// Package client is a Go client of the task tracker HTTP API.
+//
+// A Client signs in once and then makes requests on behalf of the user,
+// refreshing the access token when it expires. Failed requests are retried
+// as RetryPolicy says, errors of the API are returned as *Error and match
+// the Err variables of this package with errors.Is.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Tokens authenticate requests, the access token is refreshed with the
+// refresh token when it expires.
+type Tokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	retry      RetryPolicy
+
+	mu       sync.Mutex
+	tokens   Tokens
+	onTokens func(Tokens)
+	// refreshMu lets one request at a time refresh the tokens.
+	refreshMu sync.Mutex
+}
+
+type Option func(*Client)
+
+// WithHTTPClient sets the client requests are sent with, http.DefaultClient by default.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTokens sets the tokens of a user that signed in before.
+func WithTokens(tokens Tokens) Option {
+	return func(c *Client) {
+		c.tokens = tokens
+	}
+}
+
+// WithTokenHandler sets a function that is called with the new tokens after
+// signing in and each refresh, e.g. to store them.
+func WithTokenHandler(onTokens func(Tokens)) Option {
+	return func(c *Client) {
+		c.onTokens = onTokens
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried, DefaultRetryPolicy by default.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// New returns a client of the API served at baseURL, e.g. "http://localhost:8080/api".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		retry:      DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Tokens returns the current tokens.
+func (c *Client) Tokens() Tokens {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.tokens
+}
+
+func (c *Client) setTokens(tokens Tokens) {
+	c.mu.Lock()
+	c.tokens = tokens
+	onTokens := c.onTokens
+	c.mu.Unlock()
+
+	if onTokens != nil {
+		onTokens(tokens)
+	}
+}
+
+// request describes a call of the API.
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	// body is encoded as JSON unless it is []byte.
+	body any
+	// public requests are sent without the access token.
+	public bool
+}
+
+// do sends req and decodes the response into out unless out is nil, an
+// *io.ReadCloser out gets the response body as is. When the access token
+// has expired it is refreshed once and req is sent again.
+func (c *Client) do(ctx context.Context, req request, out any) (*http.Response, error) {
+	tokens := c.Tokens()
+	resp, err := c.send(ctx, req, out)
+	if req.public || tokens.RefreshToken == "" || !errors.Is(err, ErrUnauthorized) {
+		return resp, err
+	}
+
+	if err := c.refreshExpired(ctx, tokens); err != nil {
+		return nil, err
+	}
+	return c.send(ctx, req, out)
+}
+
+// refreshExpired refreshes the tokens unless another request already
+// replaced the expired ones.
+func (c *Client) refreshExpired(ctx context.Context, expired Tokens) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	if c.Tokens() != expired {
+		return nil
+	}
+	_, err := c.RefreshTokens(ctx, expired.RefreshToken)
+	return err
+}
+
+// send sends req, retrying it as the retry policy says.
+func (c *Client) send(ctx context.Context, req request, out any) (*http.Response, error) {
+	var body []byte
+	switch b := req.body.(type) {
+	case nil:
+	case []byte:
+		body = b
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return nil, fmt.Errorf("client: encode request: %w", err)
+		}
+		body = data
+	}
+
+	header := req.header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	if body != nil && header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "application/json")
+	}
+	if header.Get("Accept") == "" {
+		header.Set("Accept", "application/json")
+	}
+	// the API answers a retried request with the same key without applying
+	// it again. It keeps no keys for public requests, their responses may
+	// carry tokens.
+	if c.retry.MaxAttempts > 1 && !req.public && !safeMethod(req.method) && header.Get("Idempotency-Key") == "" {
+		header.Set("Idempotency-Key", newIdempotencyKey())
+	}
+
+	u := c.baseURL + req.path
+	if len(req.query) > 0 {
+		u += "?" + req.query.Encode()
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.sendOnce(ctx, req, u, header, body, out)
+		wait, retry := c.retry.next(attempt, resp, err)
+		if !retry || ctx.Err() != nil {
+			return resp, err
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return resp, err
+		}
+	}
+}
+
+func (c *Client) sendOnce(ctx context.Context, req request, u string, header http.Header, body []byte, out any) (*http.Response, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header = header.Clone()
+	if !req.public {
+		if token := c.Tokens().AccessToken; token != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		return resp, decodeError(resp)
+	}
+	if stream, ok := out.(*io.ReadCloser); ok {
+		*stream = resp.Body
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return resp, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return resp, fmt.Errorf("client: decode response: %w", err)
+	}
+	return resp, nil
+}
+
+func safeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func newIdempotencyKey() string {
+	key := make([]byte, 16)
+	rand.Read(key)
+	return hex.EncodeToString(key)
+}
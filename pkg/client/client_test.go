@@ -0,0 +1,297 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var fastRetries = RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+func writeProblem(w http.ResponseWriter, status int, problem string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{"type":"/problems/%s","title":%q,"status":%d,"request_id":"req-1"}`,
+		problem, http.StatusText(status), status)
+}
+
+func TestRetryReusesIdempotencyKey(t *testing.T) {
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, "7")
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithTokens(Tokens{AccessToken: "access"}), WithRetryPolicy(fastRetries))
+	id, err := c.CreateTag(context.Background(), TagInput{Name: "home"})
+	if err != nil {
+		t.Fatalf("CreateTag: %v", err)
+	}
+	if id != 7 {
+		t.Errorf("id = %d, want 7", id)
+	}
+	if len(keys) != 3 {
+		t.Fatalf("sent %d times, want 3", len(keys))
+	}
+	if keys[0] == "" || keys[1] != keys[0] || keys[2] != keys[0] {
+		t.Errorf("Idempotency-Key of the attempts = %q, want one key", keys)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		problem  string
+		attempts int
+	}{
+		{"overloaded", http.StatusServiceUnavailable, "", 3},
+		{"key in use", http.StatusConflict, "idempotency-key-in-use", 3},
+		{"not found", http.StatusNotFound, "task-not-found", 1},
+		{"validation", http.StatusUnprocessableEntity, "validation", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts.Add(1)
+				writeProblem(w, tt.status, tt.problem)
+			}))
+			defer srv.Close()
+
+			c := New(srv.URL, WithRetryPolicy(fastRetries))
+			if err := c.DeleteTag(context.Background(), 1); err == nil {
+				t.Fatal("DeleteTag succeeded")
+			}
+			if got := int(attempts.Load()); got != tt.attempts {
+				t.Errorf("sent %d times, want %d", got, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyWait(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 10, Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	overloaded := &Error{StatusCode: http.StatusServiceUnavailable}
+
+	for attempt, want := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		want *= time.Millisecond
+		wait, retry := p.next(attempt+1, nil, overloaded)
+		if !retry {
+			t.Fatalf("attempt %d: no retry", attempt+1)
+		}
+		if wait < want/2 || wait > want {
+			t.Errorf("attempt %d: wait %v, want between %v and %v", attempt+1, wait, want/2, want)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": {"3"}}}
+	if wait, _ := p.next(1, resp, overloaded); wait != time.Second {
+		t.Errorf("Retry-After 3 waits %v, want MaxBackoff", wait)
+	}
+	resp.Header.Set("Retry-After", "0")
+	if wait, _ := p.next(1, resp, overloaded); wait != 0 {
+		t.Errorf("Retry-After 0 waits %v", wait)
+	}
+
+	if _, retry := p.next(10, nil, overloaded); retry {
+		t.Error("retried after MaxAttempts")
+	}
+	if _, retry := p.next(1, nil, context.Canceled); retry {
+		t.Error("retried a canceled request")
+	}
+	if _, retry := p.next(1, nil, errors.New("connection reset")); !retry {
+		t.Error("network error not retried")
+	}
+}
+
+func TestRetryPolicyWithoutMaxBackoff(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, Backoff: time.Second}
+	overloaded := &Error{StatusCode: http.StatusServiceUnavailable}
+
+	for attempt, want := range []time.Duration{1, 2, 4, 8} {
+		want *= time.Second
+		wait, retry := p.next(attempt+1, nil, overloaded)
+		if !retry {
+			t.Fatalf("attempt %d: no retry", attempt+1)
+		}
+		if wait < want/2 || wait > want {
+			t.Errorf("attempt %d: wait %v, want between %v and %v", attempt+1, wait, want/2, want)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": {"30"}}}
+	if wait, _ := p.next(1, resp, overloaded); wait != 30*time.Second {
+		t.Errorf("Retry-After 30 waits %v, want 30s", wait)
+	}
+}
+
+func TestRefreshOnceOnUnauthorized(t *testing.T) {
+	var refreshes atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/auth/refresh" {
+			refreshes.Add(1)
+			fmt.Fprint(w, `{"access_token":"new-access","refresh_token":"new-refresh"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer new-access" {
+			writeProblem(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		fmt.Fprint(w, `{"id":1,"name":"Ann","email":"ann@example.com"}`)
+	}))
+	defer srv.Close()
+
+	var handled []Tokens
+	c := New(srv.URL,
+		WithTokens(Tokens{AccessToken: "expired", RefreshToken: "refresh"}),
+		WithTokenHandler(func(tokens Tokens) { handled = append(handled, tokens) }),
+		WithRetryPolicy(fastRetries))
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.CurrentUser(context.Background()); err != nil {
+				t.Errorf("CurrentUser: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := refreshes.Load(); got != 1 {
+		t.Errorf("refreshed %d times, want once", got)
+	}
+	want := Tokens{AccessToken: "new-access", RefreshToken: "new-refresh"}
+	if c.Tokens() != want {
+		t.Errorf("tokens = %+v, want %+v", c.Tokens(), want)
+	}
+	if len(handled) != 1 || handled[0] != want {
+		t.Errorf("token handler got %+v, want %+v once", handled, want)
+	}
+}
+
+func TestUnauthorizedAfterRefresh(t *testing.T) {
+	var refreshes, requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/auth/refresh" {
+			refreshes.Add(1)
+			fmt.Fprint(w, `{"access_token":"new-access","refresh_token":"new-refresh"}`)
+			return
+		}
+		requests.Add(1)
+		writeProblem(w, http.StatusUnauthorized, "unauthorized")
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithTokens(Tokens{AccessToken: "expired", RefreshToken: "refresh"}), WithRetryPolicy(fastRetries))
+	_, err := c.CurrentUser(context.Background())
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("err = %v, want ErrUnauthorized", err)
+	}
+	if refreshes.Load() != 1 || requests.Load() != 2 {
+		t.Errorf("refreshed %d times and sent %d times, want 1 and 2", refreshes.Load(), requests.Load())
+	}
+}
+
+func TestPublicRequestsHaveNoIdempotencyKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if key := r.Header.Get("Idempotency-Key"); key != "" {
+			t.Errorf("%s sent with Idempotency-Key %q", r.URL.Path, key)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("%s sent with Authorization %q", r.URL.Path, auth)
+		}
+		fmt.Fprint(w, `{"access_token":"access","refresh_token":"refresh"}`)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithRetryPolicy(fastRetries))
+	ctx := context.Background()
+	if _, err := c.SignUp(ctx, SignUpInput{Name: "Ann", Email: "ann@example.com", Password: "secret"}); err != nil {
+		t.Fatalf("SignUp: %v", err)
+	}
+	if _, err := c.SignIn(ctx, SignInInput{Email: "ann@example.com", Password: "secret"}); err != nil {
+		t.Fatalf("SignIn: %v", err)
+	}
+	if _, err := c.RefreshTokens(ctx, "refresh"); err != nil {
+		t.Fatalf("RefreshTokens: %v", err)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+		detail string
+	}{
+		{
+			name:   "problem",
+			status: http.StatusNotFound,
+			body:   `{"type":"/problems/task-not-found","title":"Not Found","status":404,"request_id":"req-1"}`,
+			want:   ErrTaskNotFound,
+		},
+		{
+			name:   "validation",
+			status: http.StatusUnprocessableEntity,
+			body:   `{"type":"/problems/validation","title":"Invalid request","status":422,"errors":[{"field":"name","rule":"required","message":"is required"}]}`,
+			want:   ErrValidation,
+		},
+		{
+			name:   "unknown problem",
+			status: http.StatusUnauthorized,
+			body:   `{"type":"/problems/something-new","status":401}`,
+			want:   ErrUnauthorized,
+		},
+		{
+			name:   "not json",
+			status: http.StatusBadGateway,
+			body:   "upstream failed",
+			detail: "upstream failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer srv.Close()
+
+			c := New(srv.URL, WithRetryPolicy(NoRetries))
+			_, err := c.GetTask(context.Background(), 1)
+
+			var apiErr *Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("err = %v, want *Error", err)
+			}
+			if apiErr.StatusCode != tt.status {
+				t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, tt.status)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && errors.Unwrap(err) != nil {
+				t.Errorf("err unwraps to %v, want nil", errors.Unwrap(err))
+			}
+			if tt.detail != "" && apiErr.Detail != tt.detail {
+				t.Errorf("Detail = %q, want %q", apiErr.Detail, tt.detail)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type CommentAuthor struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type Comment struct {
+	ID        int           `json:"id"`
+	Author    CommentAuthor `json:"author"`
+	Text      string        `json:"text"`
+	CreatedAt time.Time     `json:"created_at"`
+	EditedAt  *time.Time    `json:"edited_at"`
+}
+
+type CommentListOptions struct {
+	Limit  int
+	Cursor string
+}
+
+type CommentList struct {
+	Comments []Comment `json:"comments"`
+	// NextCursor gets the next page, it is empty on the last one.
+	NextCursor string `json:"next_cursor"`
+}
+
+type commentInput struct {
+	Text string `json:"text"`
+}
+
+func commentPath(taskID, commentID int) string {
+	return taskPath(taskID) + "/comments/" + strconv.Itoa(commentID)
+}
+
+func (c *Client) GetComments(ctx context.Context, taskID int, opts CommentListOptions) (*CommentList, error) {
+	query := url.Values{}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Cursor != "" {
+		query.Set("cursor", opts.Cursor)
+	}
+
+	var list CommentList
+	_, err := c.do(ctx, request{method: http.MethodGet, path: taskPath(taskID) + "/comments", query: query}, &list)
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (c *Client) CreateComment(ctx context.Context, taskID int, text string) (*Comment, error) {
+	var comment Comment
+	req := request{method: http.MethodPost, path: taskPath(taskID) + "/comments", body: commentInput{text}}
+	if _, err := c.do(ctx, req, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// UpdateComment changes the text of a comment, only its author can.
+func (c *Client) UpdateComment(ctx context.Context, taskID, commentID int, text string) (*Comment, error) {
+	var comment Comment
+	req := request{method: http.MethodPut, path: commentPath(taskID, commentID), body: commentInput{text}}
+	if _, err := c.do(ctx, req, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (c *Client) DeleteComment(ctx context.Context, taskID, commentID int) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: commentPath(taskID, commentID)}, nil)
+	return err
+}
@@ -0,0 +1,167 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Errors the API reports, they mirror the errors of the service. Check for
+// them with errors.Is:
+//
+//	if errors.Is(err, client.ErrTaskNotFound) { ... }
+var (
+	ErrUnauthorized = errors.New("not authenticated")
+	// ErrValidation means fields of the request were invalid, see Error.Fields.
+	ErrValidation = errors.New("invalid request")
+
+	ErrUserNotFound          = errors.New("user doesn't exists")
+	ErrUserAlreadyExists     = errors.New("user with such email already exists")
+	ErrTokenExpired          = errors.New("token has expired")
+	ErrInvalidResetToken     = errors.New("password reset token is invalid or has expired")
+	ErrInvalidVerifyToken    = errors.New("email verification token is invalid or has expired")
+	ErrEmailAlreadyVerified  = errors.New("email is already verified")
+	ErrVerificationThrottled = errors.New("verification email was sent recently, try again later")
+	ErrEmailNotVerified      = errors.New("email has to be verified first")
+	ErrTaskNotFound          = errors.New("task doesn't exists")
+	ErrTaskVersionMismatch   = errors.New("task was changed since it was read")
+	ErrTaskForbidden         = errors.New("task belongs to another user")
+	ErrInvalidTaskFilter     = errors.New("invalid task filter")
+	ErrInvalidCursor         = errors.New("invalid pagination cursor")
+	ErrInvalidPriority       = errors.New("invalid task priority")
+	ErrSubtaskCycle          = errors.New("task can't be a subtask of itself")
+	ErrSubtaskTooDeep        = errors.New("subtasks are nested too deep")
+	ErrChecklistItemNotFound = errors.New("checklist item doesn't exists")
+	ErrCommentNotFound       = errors.New("comment doesn't exists")
+	ErrCommentForbidden      = errors.New("comment belongs to another user")
+	ErrAttachmentNotFound    = errors.New("attachment doesn't exists")
+	ErrAttachmentTooLarge    = errors.New("attachment is too large")
+	ErrAttachmentType        = errors.New("attachment type is not allowed")
+	ErrDependencyNotFound    = errors.New("dependency doesn't exists")
+	ErrDependencyCycle       = errors.New("dependency would create a cycle")
+	ErrTaskBlocked           = errors.New("task is blocked by unfinished tasks")
+	ErrInvalidRecurrence     = errors.New("invalid recurrence rule")
+	ErrOccurrenceExists      = errors.New("occurrence of a recurring task already exists")
+	ErrInvalidBatch          = errors.New("invalid batch operation")
+	ErrProjectNotFound       = errors.New("project doesn't exists")
+	ErrProjectForbidden      = errors.New("project belongs to another user")
+	ErrTagNotFound           = errors.New("tag doesn't exists")
+	ErrTagForbidden          = errors.New("tag belongs to another user")
+	ErrTagAlreadyExists      = errors.New("tag with such name already exists")
+	ErrInvalidTagName        = errors.New("invalid tag name")
+	ErrIdempotencyKeyReused  = errors.New("idempotency key was already used for a different request")
+	ErrIdempotencyKeyInUse   = errors.New("request with this idempotency key is still in progress")
+	ErrWorkflowNotFound      = errors.New("workflow doesn't exists")
+	ErrInvalidWorkflow       = errors.New("invalid workflow")
+	ErrWorkflowStatusInUse   = errors.New("workflow status is used by existing tasks")
+	ErrInvalidStatus         = errors.New("status is not part of the workflow")
+	ErrStatusTransition      = errors.New("status transition is not allowed")
+	ErrPreconditionRequired  = errors.New("If-Match header is required")
+	ErrInvalidIfMatch        = errors.New("invalid If-Match header")
+)
+
+// problemErrors maps the problem types of the API to the errors above.
+var problemErrors = map[string]error{
+	"validation":                 ErrValidation,
+	"user-not-found":             ErrUserNotFound,
+	"email-taken":                ErrUserAlreadyExists,
+	"token-expired":              ErrTokenExpired,
+	"invalid-reset-token":        ErrInvalidResetToken,
+	"invalid-verification-token": ErrInvalidVerifyToken,
+	"email-already-verified":     ErrEmailAlreadyVerified,
+	"verification-throttled":     ErrVerificationThrottled,
+	"email-not-verified":         ErrEmailNotVerified,
+	"task-not-found":             ErrTaskNotFound,
+	"task-forbidden":             ErrTaskForbidden,
+	"task-version-mismatch":      ErrTaskVersionMismatch,
+	"invalid-task-filter":        ErrInvalidTaskFilter,
+	"invalid-cursor":             ErrInvalidCursor,
+	"invalid-status":             ErrInvalidStatus,
+	"invalid-priority":           ErrInvalidPriority,
+	"invalid-recurrence":         ErrInvalidRecurrence,
+	"status-transition":          ErrStatusTransition,
+	"subtask-cycle":              ErrSubtaskCycle,
+	"subtask-too-deep":           ErrSubtaskTooDeep,
+	"dependency-cycle":           ErrDependencyCycle,
+	"dependency-not-found":       ErrDependencyNotFound,
+	"task-blocked":               ErrTaskBlocked,
+	"occurrence-exists":          ErrOccurrenceExists,
+	"invalid-batch":              ErrInvalidBatch,
+	"checklist-item-not-found":   ErrChecklistItemNotFound,
+	"comment-not-found":          ErrCommentNotFound,
+	"comment-forbidden":          ErrCommentForbidden,
+	"attachment-not-found":       ErrAttachmentNotFound,
+	"attachment-too-large":       ErrAttachmentTooLarge,
+	"attachment-type":            ErrAttachmentType,
+	"project-not-found":          ErrProjectNotFound,
+	"project-forbidden":          ErrProjectForbidden,
+	"tag-not-found":              ErrTagNotFound,
+	"tag-forbidden":              ErrTagForbidden,
+	"tag-exists":                 ErrTagAlreadyExists,
+	"invalid-tag-name":           ErrInvalidTagName,
+	"workflow-not-found":         ErrWorkflowNotFound,
+	"invalid-workflow":           ErrInvalidWorkflow,
+	"workflow-status-in-use":     ErrWorkflowStatusInUse,
+	"idempotency-key-reused":     ErrIdempotencyKeyReused,
+	"idempotency-key-in-use":     ErrIdempotencyKeyInUse,
+	"if-match-required":          ErrPreconditionRequired,
+	"invalid-if-match":           ErrInvalidIfMatch,
+}
+
+// Error is an error response of the API, a problem details object (RFC 7807).
+type Error struct {
+	StatusCode int          `json:"status"`
+	Type       string       `json:"type"`
+	Title      string       `json:"title"`
+	Detail     string       `json:"detail"`
+	RequestID  string       `json:"request_id"`
+	Fields     []FieldError `json:"errors"`
+}
+
+// FieldError tells which field of a request failed validation and why.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	msg := e.Title
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	for _, f := range e.Fields {
+		msg += fmt.Sprintf("; %s %s", f.Field, f.Message)
+	}
+	return msg
+}
+
+// Unwrap returns the error of the problem type, nil for types unknown to
+// this package.
+func (e *Error) Unwrap() error {
+	if _, name, found := strings.Cut(e.Type, "/problems/"); found {
+		if err, ok := problemErrors[name]; ok {
+			return err
+		}
+	}
+	if e.StatusCode == http.StatusUnauthorized {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+func decodeError(resp *http.Response) error {
+	apiErr := &Error{}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err := json.Unmarshal(body, apiErr); err != nil {
+		apiErr.Detail = string(body)
+	}
+	apiErr.StatusCode = resp.StatusCode
+	return apiErr
+}
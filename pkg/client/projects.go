@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Project struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type ProjectInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func projectPath(projectID int) string {
+	return "/projects/" + strconv.Itoa(projectID)
+}
+
+// CreateProject creates a project and returns its ID.
+func (c *Client) CreateProject(ctx context.Context, input ProjectInput) (int, error) {
+	return c.create(ctx, "/projects", input)
+}
+
+func (c *Client) GetProject(ctx context.Context, projectID int) (*Project, error) {
+	var project Project
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: projectPath(projectID)}, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+func (c *Client) ListProjects(ctx context.Context) ([]Project, error) {
+	var projects []Project
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/projects"}, &projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+func (c *Client) UpdateProject(ctx context.Context, projectID int, input ProjectInput) error {
+	_, err := c.do(ctx, request{method: http.MethodPut, path: projectPath(projectID), body: input}, nil)
+	return err
+}
+
+func (c *Client) DeleteProject(ctx context.Context, projectID int) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: projectPath(projectID)}, nil)
+	return err
+}
+
+// ListProjectTasks lists the tasks of a project, opts are applied as by ListTasks.
+func (c *Client) ListProjectTasks(ctx context.Context, projectID int, opts TaskListOptions) (*TaskList, error) {
+	var list TaskList
+	req := request{method: http.MethodGet, path: projectPath(projectID) + "/tasks", query: opts.values()}
+	if _, err := c.do(ctx, req, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
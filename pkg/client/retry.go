@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy tells how failed requests are retried. Requests are retried
+// after network errors and responses that say the API is overloaded or a
+// request with the same Idempotency-Key is in progress. Each retry waits
+// twice as long as the one before, starting at Backoff and up to
+// MaxBackoff, unless the response says how long to wait with Retry-After.
+// A MaxBackoff of 0 doesn't cap the waits.
+//
+// Requests that change something are sent with an Idempotency-Key when they
+// may be retried, so a retry never applies a change twice. Requests sent
+// without the access token, like SignIn, go without one.
+type RetryPolicy struct {
+	// MaxAttempts is how many times a request is sent at most, 1 or less
+	// disables retries.
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	Backoff:     200 * time.Millisecond,
+	MaxBackoff:  5 * time.Second,
+}
+
+// NoRetries sends every request once.
+var NoRetries = RetryPolicy{MaxAttempts: 1}
+
+// next tells whether the request should be sent again after attempt and
+// how long to wait before that.
+func (p RetryPolicy) next(attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || err == nil {
+		return 0, false
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) && !retryable(apiErr) {
+		return 0, false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return 0, false
+	}
+
+	if resp != nil {
+		if wait, ok := retryAfter(resp); ok {
+			if p.MaxBackoff > 0 {
+				wait = min(wait, p.MaxBackoff)
+			}
+			return wait, true
+		}
+	}
+	return p.backoff(attempt), true
+}
+
+func retryable(err *Error) bool {
+	switch err.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return errors.Is(err, ErrIdempotencyKeyInUse)
+}
+
+// backoff returns the wait before the retry after attempt, with jitter so
+// clients that failed together don't retry together.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.Backoff << (attempt - 1)
+	if wait <= 0 || (p.MaxBackoff > 0 && wait > p.MaxBackoff) {
+		wait = p.MaxBackoff
+	}
+	if wait <= 0 {
+		return 0
+	}
+	return wait/2 + rand.N(wait/2+1)
+}
+
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
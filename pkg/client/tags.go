@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+type TagInput struct {
+	Name string `json:"name"`
+	// Color is a hex color like "#ff8800".
+	Color string `json:"color,omitempty"`
+}
+
+// TagUpdate changes the fields that are set.
+type TagUpdate struct {
+	Name  *string `json:"name,omitempty"`
+	Color *string `json:"color,omitempty"`
+}
+
+func tagPath(tagID int) string {
+	return "/tags/" + strconv.Itoa(tagID)
+}
+
+// CreateTag creates a tag and returns its ID.
+func (c *Client) CreateTag(ctx context.Context, input TagInput) (int, error) {
+	return c.create(ctx, "/tags", input)
+}
+
+func (c *Client) ListTags(ctx context.Context) ([]Tag, error) {
+	var tags []Tag
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/tags"}, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (c *Client) UpdateTag(ctx context.Context, tagID int, update TagUpdate) error {
+	_, err := c.do(ctx, request{method: http.MethodPatch, path: tagPath(tagID), body: update}, nil)
+	return err
+}
+
+func (c *Client) DeleteTag(ctx context.Context, tagID int) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: tagPath(tagID)}, nil)
+	return err
+}
@@ -0,0 +1,345 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Task struct {
+	ID           int        `json:"id"`
+	ProjectID    *int       `json:"project_id"`
+	ParentID     *int       `json:"parent_id"`
+	Status       string     `json:"status"`
+	Title        string     `json:"title"`
+	Text         string     `json:"text"`
+	Priority     string     `json:"priority"`
+	DueAt        *time.Time `json:"due_at"`
+	IsOverdue    bool       `json:"is_overdue"`
+	CompletedAt  *time.Time `json:"completed_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	AutoComplete bool       `json:"auto_complete"`
+	Progress     Progress   `json:"progress"`
+	Tags         []Tag      `json:"tags"`
+	BlockedBy    []int      `json:"blocked_by"`
+	Blocking     []int      `json:"blocking"`
+	Recurrence   string     `json:"recurrence,omitempty"`
+	SeriesID     *int       `json:"series_id"`
+	Occurrence   int        `json:"occurrence"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+	Version      int        `json:"version"`
+}
+
+// Progress sums up subtasks and checklist items of a task.
+type Progress struct {
+	Done  int    `json:"done"`
+	Total int    `json:"total"`
+	Text  string `json:"text"`
+}
+
+type Tag struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// TaskInput is what a task is created with or replaced by.
+type TaskInput struct {
+	ProjectID    *int       `json:"project_id,omitempty"`
+	ParentID     *int       `json:"parent_id,omitempty"`
+	Title        string     `json:"title"`
+	Status       string     `json:"status,omitempty"`
+	Text         string     `json:"text,omitempty"`
+	Priority     string     `json:"priority,omitempty"`
+	DueAt        *time.Time `json:"due_at,omitempty"`
+	AutoComplete *bool      `json:"auto_complete,omitempty"`
+	// Recurrence is an RRULE, nil keeps the current one and "" removes it.
+	Recurrence *string `json:"recurrence,omitempty"`
+}
+
+// TaskPatch changes the fields that are set and leaves the others as they are.
+type TaskPatch struct {
+	Title        *string    `json:"title,omitempty"`
+	Status       *string    `json:"status,omitempty"`
+	Text         *string    `json:"text,omitempty"`
+	Priority     *string    `json:"priority,omitempty"`
+	DueAt        *time.Time `json:"due_at,omitempty"`
+	AutoComplete *bool      `json:"auto_complete,omitempty"`
+	Recurrence   *string    `json:"recurrence,omitempty"`
+	ProjectID    *int       `json:"project_id,omitempty"`
+}
+
+// WriteOptions tune how a task is changed.
+type WriteOptions struct {
+	// Version makes the change go through only while the task is at that
+	// version, 0 changes any version.
+	Version int
+	// Force completes a task even if it is blocked by unfinished tasks.
+	Force bool
+}
+
+func (o WriteOptions) request(req request) request {
+	if o.Version != 0 {
+		req.header = http.Header{"If-Match": {`"` + strconv.Itoa(o.Version) + `"`}}
+	}
+	if o.Force {
+		req.query = url.Values{"force": {"true"}}
+	}
+	return req
+}
+
+type TaskListOptions struct {
+	Status string
+	Query  string
+	Tags   []string
+	// TagMode is either "any" or "all".
+	TagMode       string
+	Overdue       bool
+	SeriesID      int
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	CompletedFrom *time.Time
+	CompletedTo   *time.Time
+	Sort          string
+	Order         string
+	Limit         int
+	Cursor        string
+}
+
+func (o TaskListOptions) values() url.Values {
+	values := url.Values{}
+	set := func(name, value string) {
+		if value != "" {
+			values.Set(name, value)
+		}
+	}
+	set("status", o.Status)
+	set("q", o.Query)
+	set("tag_mode", o.TagMode)
+	set("sort", o.Sort)
+	set("order", o.Order)
+	set("cursor", o.Cursor)
+	for _, tag := range o.Tags {
+		values.Add("tag", tag)
+	}
+	if o.Overdue {
+		values.Set("overdue", "true")
+	}
+	if o.SeriesID != 0 {
+		values.Set("series_id", strconv.Itoa(o.SeriesID))
+	}
+	setTime := func(name string, t *time.Time) {
+		if t != nil {
+			values.Set(name, t.Format(time.RFC3339))
+		}
+	}
+	setTime("created_from", o.CreatedFrom)
+	setTime("created_to", o.CreatedTo)
+	setTime("completed_from", o.CompletedFrom)
+	setTime("completed_to", o.CompletedTo)
+	if o.Limit > 0 {
+		values.Set("limit", strconv.Itoa(o.Limit))
+	}
+	return values
+}
+
+// TaskGroup holds the tasks in one workflow status.
+type TaskGroup struct {
+	Status   string `json:"status"`
+	Category string `json:"category"`
+	Tasks    []Task `json:"tasks"`
+}
+
+type TaskList struct {
+	Groups []TaskGroup `json:"groups"`
+	// NextCursor gets the next page, it is empty on the last one.
+	NextCursor string `json:"next_cursor"`
+}
+
+func taskPath(taskID int) string {
+	return "/tasks/" + strconv.Itoa(taskID)
+}
+
+// CreateTask creates a task and returns its ID.
+func (c *Client) CreateTask(ctx context.Context, input TaskInput) (int, error) {
+	return c.create(ctx, "/tasks", input)
+}
+
+// create posts input to path, the API answers with the ID of what it created.
+func (c *Client) create(ctx context.Context, path string, input any) (int, error) {
+	var body json.RawMessage
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: path, body: input}, &body); err != nil {
+		return 0, err
+	}
+	id, err := strconv.Atoi(strings.TrimSpace(string(body)))
+	if err != nil {
+		return 0, fmt.Errorf("client: unexpected ID %q", body)
+	}
+	return id, nil
+}
+
+func (c *Client) GetTask(ctx context.Context, taskID int) (*Task, error) {
+	var task Task
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: taskPath(taskID)}, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (c *Client) ListTasks(ctx context.Context, opts TaskListOptions) (*TaskList, error) {
+	var list TaskList
+	_, err := c.do(ctx, request{method: http.MethodGet, path: "/tasks", query: opts.values()}, &list)
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// UpdateTask replaces the task with input.
+func (c *Client) UpdateTask(ctx context.Context, taskID int, input TaskInput, opts WriteOptions) error {
+	_, err := c.do(ctx, opts.request(request{method: http.MethodPut, path: taskPath(taskID), body: input}), nil)
+	return err
+}
+
+// PatchTask changes some fields of the task and returns it as it is now.
+func (c *Client) PatchTask(ctx context.Context, taskID int, patch TaskPatch, opts WriteOptions) (*Task, error) {
+	req := opts.request(request{method: http.MethodPatch, path: taskPath(taskID), body: patch})
+	if req.header == nil {
+		req.header = http.Header{}
+	}
+	req.header.Set("Content-Type", "application/merge-patch+json")
+
+	var task Task
+	if _, err := c.do(ctx, req, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// DeleteTask moves the task to the trash.
+func (c *Client) DeleteTask(ctx context.Context, taskID int, opts WriteOptions) error {
+	_, err := c.do(ctx, opts.request(request{method: http.MethodDelete, path: taskPath(taskID)}), nil)
+	return err
+}
+
+// MoveTask moves the task to a project, nil takes it out of its project.
+func (c *Client) MoveTask(ctx context.Context, taskID int, projectID *int, opts WriteOptions) error {
+	body := struct {
+		ProjectID *int `json:"project_id"`
+	}{projectID}
+	_, err := c.do(ctx, opts.request(request{method: http.MethodPut, path: taskPath(taskID) + "/project", body: body}), nil)
+	return err
+}
+
+// SetTaskParent makes the task a subtask of parentID, nil makes it a top level task.
+func (c *Client) SetTaskParent(ctx context.Context, taskID int, parentID *int, opts WriteOptions) error {
+	body := struct {
+		ParentID *int `json:"parent_id"`
+	}{parentID}
+	_, err := c.do(ctx, opts.request(request{method: http.MethodPut, path: taskPath(taskID) + "/parent", body: body}), nil)
+	return err
+}
+
+func (c *Client) GetSubtasks(ctx context.Context, taskID int) ([]Task, error) {
+	var tasks []Task
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: taskPath(taskID) + "/subtasks"}, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// TaskEvent is one entry of a task's history, Field and the values are only
+// set for updates.
+type TaskEvent struct {
+	ID        int64     `json:"id"`
+	TaskID    int       `json:"task_id"`
+	UserID    int       `json:"user_id"`
+	Action    string    `json:"action"`
+	Field     *string   `json:"field"`
+	OldValue  *string   `json:"old_value"`
+	NewValue  *string   `json:"new_value"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (c *Client) GetTaskHistory(ctx context.Context, taskID int) ([]TaskEvent, error) {
+	var events []TaskEvent
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: taskPath(taskID) + "/history"}, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// AddDependency makes the task blocked by the task blockedByID.
+func (c *Client) AddDependency(ctx context.Context, taskID, blockedByID int) error {
+	body := struct {
+		BlockedBy int `json:"blocked_by"`
+	}{blockedByID}
+	_, err := c.do(ctx, request{method: http.MethodPost, path: taskPath(taskID) + "/dependencies", body: body}, nil)
+	return err
+}
+
+func (c *Client) RemoveDependency(ctx context.Context, taskID, blockedByID int) error {
+	path := taskPath(taskID) + "/dependencies/" + strconv.Itoa(blockedByID)
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: path}, nil)
+	return err
+}
+
+func (c *Client) AttachTag(ctx context.Context, taskID, tagID int) error {
+	_, err := c.do(ctx, request{method: http.MethodPut, path: taskPath(taskID) + "/tags/" + strconv.Itoa(tagID)}, nil)
+	return err
+}
+
+func (c *Client) DetachTag(ctx context.Context, taskID, tagID int) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: taskPath(taskID) + "/tags/" + strconv.Itoa(tagID)}, nil)
+	return err
+}
+
+// Bulk operations.
+const (
+	BulkUpdateStatus = "update_status"
+	BulkSetTags      = "set_tags"
+	BulkMoveProject  = "move_project"
+	BulkDelete       = "delete"
+)
+
+// BulkOperation applies one change to each of TaskIDs, Op is one of the
+// Bulk constants.
+type BulkOperation struct {
+	Op        string `json:"op"`
+	TaskIDs   []int  `json:"task_ids"`
+	Status    string `json:"status,omitempty"`
+	Force     bool   `json:"force,omitempty"`
+	TagIDs    []int  `json:"tag_ids,omitempty"`
+	ProjectID *int   `json:"project_id,omitempty"`
+}
+
+type BulkInput struct {
+	Operations []BulkOperation `json:"operations"`
+	// Atomic applies either every item or none of them.
+	Atomic bool `json:"atomic"`
+}
+
+// BulkItemResult reports how one operation went for one task.
+type BulkItemResult struct {
+	Operation int    `json:"operation"`
+	TaskID    int    `json:"task_id"`
+	OK        bool   `json:"ok"`
+	Error     string `json:"error,omitempty"`
+}
+
+// BulkUpdateTasks applies the operations and reports how each went per task.
+func (c *Client) BulkUpdateTasks(ctx context.Context, input BulkInput) ([]BulkItemResult, error) {
+	var resp struct {
+		Results []BulkItemResult `json:"results"`
+	}
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/tasks/bulk", body: input}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Results, nil
+}
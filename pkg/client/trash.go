@@ -0,0 +1,31 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+func trashPath(taskID int) string {
+	return "/trash/" + strconv.Itoa(taskID)
+}
+
+// GetTrash returns the deleted tasks, they are purged some time after deletion.
+func (c *Client) GetTrash(ctx context.Context) ([]Task, error) {
+	var tasks []Task
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/trash"}, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (c *Client) RestoreTask(ctx context.Context, taskID int) error {
+	_, err := c.do(ctx, request{method: http.MethodPost, path: trashPath(taskID) + "/restore"}, nil)
+	return err
+}
+
+// PurgeTask removes a deleted task for good.
+func (c *Client) PurgeTask(ctx context.Context, taskID int) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: trashPath(taskID)}, nil)
+	return err
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type SignUpInput struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type SignInInput struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type User struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// VerifiedAt is nil until the user verifies their email.
+	VerifiedAt *time.Time `json:"verified_at"`
+}
+
+// SignUp creates a user and signs them in.
+func (c *Client) SignUp(ctx context.Context, input SignUpInput) (Tokens, error) {
+	return c.authenticate(ctx, "/users/sign-up", input)
+}
+
+// SignIn signs the user in, later requests are made on their behalf.
+func (c *Client) SignIn(ctx context.Context, input SignInInput) (Tokens, error) {
+	return c.authenticate(ctx, "/users/sign-in", input)
+}
+
+// RefreshTokens exchanges the refresh token for new tokens. Requests refresh
+// expired tokens on their own, so it is rarely called directly.
+func (c *Client) RefreshTokens(ctx context.Context, refreshToken string) (Tokens, error) {
+	return c.authenticate(ctx, "/users/auth/refresh", struct {
+		Token string `json:"token"`
+	}{refreshToken})
+}
+
+// ForgotPassword asks the API to email a password reset link. It succeeds
+// whether or not the email belongs to a user.
+func (c *Client) ForgotPassword(ctx context.Context, email string) error {
+	_, err := c.do(ctx, request{method: http.MethodPost, path: "/users/password/forgot", body: struct {
+		Email string `json:"email"`
+	}{email}, public: true}, nil)
+	return err
+}
+
+// ResetPassword sets a new password with the token of a reset link. All
+// sessions of the user are signed out.
+func (c *Client) ResetPassword(ctx context.Context, token, password string) error {
+	_, err := c.do(ctx, request{method: http.MethodPost, path: "/users/password/reset", body: struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}{token, password}, public: true}, nil)
+	return err
+}
+
+// VerifyEmail verifies the email of a user with the token of a verification
+// link.
+func (c *Client) VerifyEmail(ctx context.Context, token string) error {
+	_, err := c.do(ctx, request{method: http.MethodPost, path: "/users/verify", body: struct {
+		Token string `json:"token"`
+	}{token}, public: true}, nil)
+	return err
+}
+
+// ResendVerification emails a new verification link to the signed in user.
+// It fails with ErrVerificationThrottled when a link was sent recently.
+func (c *Client) ResendVerification(ctx context.Context) error {
+	_, err := c.do(ctx, request{method: http.MethodPost, path: "/users/verify/resend"}, nil)
+	return err
+}
+
+func (c *Client) authenticate(ctx context.Context, path string, input any) (Tokens, error) {
+	var tokens Tokens
+	_, err := c.do(ctx, request{method: http.MethodPost, path: path, body: input, public: true}, &tokens)
+	if err != nil {
+		return Tokens{}, err
+	}
+	c.setTokens(tokens)
+	return tokens, nil
+}
+
+// CurrentUser returns the signed in user.
+func (c *Client) CurrentUser(ctx context.Context) (*User, error) {
+	var user User
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/users"}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// Status categories tell what a workflow status means.
+const (
+	CategoryTodo       = "todo"
+	CategoryInProgress = "in_progress"
+	CategoryDone       = "done"
+)
+
+type WorkflowStatus struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+type WorkflowTransition struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type Workflow struct {
+	Statuses    []WorkflowStatus     `json:"statuses"`
+	Transitions []WorkflowTransition `json:"transitions"`
+	IsDefault   bool                 `json:"is_default"`
+}
+
+// GetWorkflow returns the statuses the user's tasks go through.
+func (c *Client) GetWorkflow(ctx context.Context) (*Workflow, error) {
+	var wf Workflow
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/workflow"}, &wf); err != nil {
+		return nil, err
+	}
+	return &wf, nil
+}
+
+type WorkflowInput struct {
+	Statuses    []WorkflowStatus     `json:"statuses"`
+	Transitions []WorkflowTransition `json:"transitions"`
+}
+
+// SetWorkflow replaces the statuses the user's tasks go through.
+func (c *Client) SetWorkflow(ctx context.Context, input WorkflowInput) error {
+	_, err := c.do(ctx, request{method: http.MethodPut, path: "/workflow", body: input}, nil)
+	return err
+}
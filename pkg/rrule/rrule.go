@@ -0,0 +1,327 @@
+// Package rrule implements the subset of RFC 5545 recurrence rules tasks use:
+// FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, BYDAY, COUNT and UNTIL.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// Weekday is a BYDAY entry. N picks the n-th such day of the month,
+// counting from the end when negative; zero means every such day.
+type Weekday struct {
+	Day time.Weekday
+	N   int
+}
+
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []Weekday
+	Count    int
+	Until    *time.Time
+}
+
+var dayNames = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// dayCodes is dayNames the other way round.
+var dayCodes = func() map[time.Weekday]string {
+	codes := make(map[time.Weekday]string, len(dayNames))
+	for code, day := range dayNames {
+		codes[day] = code
+	}
+	return codes
+}()
+
+// maxSteps bounds the search for the next occurrence, so that a rule that
+// can never match, like the 5th Monday every 12 months, doesn't spin forever.
+const maxSteps = 1000
+
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, ErrInvalidRule
+	}
+
+	r := &Rule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRule, part)
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = Frequency(strings.ToUpper(value))
+			if r.Freq != Daily && r.Freq != Weekly && r.Freq != Monthly && r.Freq != Yearly {
+				return nil, fmt.Errorf("%w: unsupported FREQ %q", ErrInvalidRule, value)
+			}
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+			if err != nil || r.Interval < 1 {
+				return nil, fmt.Errorf("%w: bad INTERVAL %q", ErrInvalidRule, value)
+			}
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+			if err != nil || r.Count < 1 {
+				return nil, fmt.Errorf("%w: bad COUNT %q", ErrInvalidRule, value)
+			}
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: bad UNTIL %q", ErrInvalidRule, value)
+			}
+			r.Until = &until
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				wd, err := parseWeekday(strings.ToUpper(day))
+				if err != nil {
+					return nil, fmt.Errorf("%w: bad BYDAY %q", ErrInvalidRule, day)
+				}
+				r.ByDay = append(r.ByDay, wd)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %q", ErrInvalidRule, key)
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if r.Count > 0 && r.Until != nil {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL can't be used together", ErrInvalidRule)
+	}
+	for _, wd := range r.ByDay {
+		if wd.N != 0 && r.Freq != Monthly {
+			return nil, fmt.Errorf("%w: numbered BYDAY only works with MONTHLY", ErrInvalidRule)
+		}
+	}
+	if len(r.ByDay) > 0 && r.Freq == Yearly {
+		return nil, fmt.Errorf("%w: BYDAY isn't supported with YEARLY", ErrInvalidRule)
+	}
+
+	return r, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("20060102T150405", value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("20060102", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// a date means the whole of that day
+	return t.Add(24*time.Hour - time.Second), nil
+}
+
+func parseWeekday(s string) (Weekday, error) {
+	if len(s) < 2 {
+		return Weekday{}, ErrInvalidRule
+	}
+	day, ok := dayNames[s[len(s)-2:]]
+	if !ok {
+		return Weekday{}, ErrInvalidRule
+	}
+	wd := Weekday{Day: day}
+	if prefix := s[:len(s)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return Weekday{}, ErrInvalidRule
+		}
+		wd.N = n
+	}
+	return wd, nil
+}
+
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, wd := range r.ByDay {
+			name := dayCodes[wd.Day]
+			if wd.N != 0 {
+				name = strconv.Itoa(wd.N) + name
+			}
+			days = append(days, name)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the occurrence that follows prev, which is the n-th one of the
+// series counting from 1. It reports false once COUNT or UNTIL end the series.
+func (r Rule) Next(prev time.Time, n int) (time.Time, bool) {
+	if r.Count > 0 && n >= r.Count {
+		return time.Time{}, false
+	}
+
+	var next time.Time
+	var ok bool
+	switch r.Freq {
+	case Daily:
+		next, ok = r.nextDaily(prev)
+	case Weekly:
+		next, ok = r.nextWeekly(prev)
+	case Monthly:
+		next, ok = r.nextMonthly(prev)
+	case Yearly:
+		next, ok = r.nextYearly(prev)
+	}
+	if !ok {
+		return time.Time{}, false
+	}
+	if r.Until != nil && next.After(*r.Until) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+func (r Rule) matchesDay(t time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, wd := range r.ByDay {
+		if wd.Day == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) nextDaily(prev time.Time) (time.Time, bool) {
+	next := prev
+	for i := 0; i < maxSteps; i++ {
+		next = next.AddDate(0, 0, r.Interval)
+		if r.matchesDay(next) {
+			return next, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (r Rule) nextWeekly(prev time.Time) (time.Time, bool) {
+	if len(r.ByDay) == 0 {
+		return prev.AddDate(0, 0, 7*r.Interval), true
+	}
+
+	// weeks start on Monday, as WKST defaults to MO
+	offset := (int(prev.Weekday()) + 6) % 7
+	weekStart := prev.AddDate(0, 0, -offset)
+	for i := 0; i < maxSteps; i++ {
+		for d := 0; d < 7; d++ {
+			day := weekStart.AddDate(0, 0, d)
+			if day.After(prev) && r.matchesDay(day) {
+				return day, true
+			}
+		}
+		weekStart = weekStart.AddDate(0, 0, 7*r.Interval)
+	}
+	return time.Time{}, false
+}
+
+func (r Rule) nextMonthly(prev time.Time) (time.Time, bool) {
+	year, month, _ := prev.Date()
+	for i := 0; i < maxSteps; i++ {
+		candidates := r.monthDays(prev, year, month)
+		for _, day := range candidates {
+			if day.After(prev) {
+				return day, true
+			}
+		}
+		month += time.Month(r.Interval)
+		for month > 12 {
+			month -= 12
+			year++
+		}
+	}
+	return time.Time{}, false
+}
+
+// monthDays lists the days of a month the rule picks, at the time of day of prev.
+func (r Rule) monthDays(prev time.Time, year int, month time.Month) []time.Time {
+	at := func(day int) time.Time {
+		return time.Date(year, month, day, prev.Hour(), prev.Minute(), prev.Second(), prev.Nanosecond(), prev.Location())
+	}
+	daysIn := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+	if len(r.ByDay) == 0 {
+		// months too short for the day are skipped
+		if prev.Day() > daysIn {
+			return nil
+		}
+		return []time.Time{at(prev.Day())}
+	}
+
+	var days []time.Time
+	for _, wd := range r.ByDay {
+		var matching []int
+		for d := 1; d <= daysIn; d++ {
+			if at(d).Weekday() == wd.Day {
+				matching = append(matching, d)
+			}
+		}
+		switch {
+		case wd.N == 0:
+			for _, d := range matching {
+				days = append(days, at(d))
+			}
+		case wd.N > 0 && wd.N <= len(matching):
+			days = append(days, at(matching[wd.N-1]))
+		case wd.N < 0 && -wd.N <= len(matching):
+			days = append(days, at(matching[len(matching)+wd.N]))
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
+
+func (r Rule) nextYearly(prev time.Time) (time.Time, bool) {
+	year, month, day := prev.Date()
+	for i := 0; i < maxSteps; i++ {
+		year += r.Interval
+		// Feb 29 only happens in leap years
+		if time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Day() != day {
+			continue
+		}
+		return time.Date(year, month, day, prev.Hour(), prev.Minute(), prev.Second(), prev.Nanosecond(), prev.Location()), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,214 @@
+package rrule
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+}
+
+// series returns the occurrences that follow start, which is the first one,
+// until the rule ends or max of them are found.
+func series(t *testing.T, rule string, start time.Time, max int) []time.Time {
+	t.Helper()
+	r, err := Parse(rule)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", rule, err)
+	}
+	var got []time.Time
+	prev := start
+	for n := 1; len(got) < max; n++ {
+		next, ok := r.Next(prev, n)
+		if !ok {
+			break
+		}
+		got = append(got, next)
+		prev = next
+	}
+	return got
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		max   int
+		want  []time.Time
+	}{
+		{
+			name:  "daily with interval",
+			rule:  "FREQ=DAILY;INTERVAL=3",
+			start: date(2026, time.February, 26),
+			max:   3,
+			want:  []time.Time{date(2026, time.March, 1), date(2026, time.March, 4), date(2026, time.March, 7)},
+		},
+		{
+			name:  "monthly on the 31st skips short months",
+			rule:  "FREQ=MONTHLY",
+			start: date(2026, time.January, 31),
+			max:   4,
+			want: []time.Time{
+				date(2026, time.March, 31), date(2026, time.May, 31),
+				date(2026, time.July, 31), date(2026, time.August, 31),
+			},
+		},
+		{
+			name:  "weekly by day every other week",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR",
+			start: date(2026, time.October, 5), // Monday
+			max:   5,
+			want: []time.Time{
+				date(2026, time.October, 9), date(2026, time.October, 19), date(2026, time.October, 23),
+				date(2026, time.November, 2), date(2026, time.November, 6),
+			},
+		},
+		{
+			name:  "weekly without by day",
+			rule:  "FREQ=WEEKLY;INTERVAL=3",
+			start: date(2026, time.October, 7),
+			max:   2,
+			want:  []time.Time{date(2026, time.October, 28), date(2026, time.November, 18)},
+		},
+		{
+			name:  "monthly on the last Friday",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR",
+			start: date(2026, time.January, 30),
+			max:   3,
+			want:  []time.Time{date(2026, time.February, 27), date(2026, time.March, 27), date(2026, time.April, 24)},
+		},
+		{
+			name:  "monthly on the 5th Monday skips months without one",
+			rule:  "FREQ=MONTHLY;BYDAY=5MO",
+			start: date(2026, time.March, 30),
+			max:   3,
+			want:  []time.Time{date(2026, time.June, 29), date(2026, time.August, 31), date(2026, time.November, 30)},
+		},
+		{
+			name:  "monthly on the 1st and 3rd Tuesday",
+			rule:  "FREQ=MONTHLY;BYDAY=3TU,1TU",
+			start: date(2026, time.October, 6),
+			max:   3,
+			want:  []time.Time{date(2026, time.October, 20), date(2026, time.November, 3), date(2026, time.November, 17)},
+		},
+		{
+			name:  "yearly on Feb 29 skips common years",
+			rule:  "FREQ=YEARLY",
+			start: date(2024, time.February, 29),
+			max:   2,
+			want:  []time.Time{date(2028, time.February, 29), date(2032, time.February, 29)},
+		},
+		{
+			name:  "count ends the series",
+			rule:  "FREQ=DAILY;COUNT=3",
+			start: date(2026, time.October, 18),
+			max:   10,
+			want:  []time.Time{date(2026, time.October, 19), date(2026, time.October, 20)},
+		},
+		{
+			name:  "until with a time ends the series",
+			rule:  "FREQ=DAILY;UNTIL=20261020T093000Z",
+			start: date(2026, time.October, 18),
+			max:   10,
+			want:  []time.Time{date(2026, time.October, 19), date(2026, time.October, 20)},
+		},
+		{
+			name:  "until before the time of day excludes that day",
+			rule:  "FREQ=DAILY;UNTIL=20261020T090000Z",
+			start: date(2026, time.October, 18),
+			max:   10,
+			want:  []time.Time{date(2026, time.October, 19)},
+		},
+		{
+			name:  "until as a date includes the whole day",
+			rule:  "FREQ=DAILY;UNTIL=20261020",
+			start: date(2026, time.October, 18),
+			max:   10,
+			want:  []time.Time{date(2026, time.October, 19), date(2026, time.October, 20)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := series(t, tt.rule, tt.start, tt.max)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNextGivesUp checks that a rule that never matches ends the series
+// instead of searching forever.
+func TestNextGivesUp(t *testing.T) {
+	// every 7 days from a Monday never lands on a Tuesday
+	r, err := Parse("FREQ=DAILY;INTERVAL=7;BYDAY=TU")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next, ok := r.Next(date(2026, time.October, 5), 1); ok {
+		t.Errorf("got %v, want the series to end", next)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	rules := []string{
+		"",
+		"RRULE:",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;INTERVAL=x",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=DAILY;COUNT=2;UNTIL=20261020",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=M",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=MONTHLY;BYDAY=0MO",
+		"FREQ=MONTHLY;BYDAY=-6FR",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=YEARLY;BYDAY=MO",
+		"FREQ=DAILY;BYMONTH=1",
+		"FREQ=DAILY;INTERVAL",
+	}
+	for _, rule := range rules {
+		if _, err := Parse(rule); !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("Parse(%q) = %v, want ErrInvalidRule", rule, err)
+		}
+	}
+}
+
+func TestStringRoundTrip(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"RRULE:freq=weekly;interval=2;byday=mo,we,su", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE,SU"},
+		{"FREQ=MONTHLY;BYDAY=-1FR,2TU;COUNT=5", "FREQ=MONTHLY;BYDAY=-1FR,2TU;COUNT=5"},
+		{"FREQ=YEARLY;INTERVAL=1;UNTIL=20301231T120000Z", "FREQ=YEARLY;UNTIL=20301231T120000Z"},
+		{"FREQ=DAILY;UNTIL=20301231", "FREQ=DAILY;UNTIL=20301231T235959Z"},
+	}
+	for _, tt := range tests {
+		r, err := Parse(tt.rule)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.rule, err)
+		}
+		if got := r.String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.rule, got, tt.want)
+		}
+		again, err := Parse(r.String())
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", r.String(), err)
+		}
+		if !reflect.DeepEqual(again, r) {
+			t.Errorf("Parse(%q) = %+v, want %+v", r.String(), again, r)
+		}
+	}
+}